@@ -0,0 +1,162 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// ItemShare is the part of one item's price charged to a card
+type ItemShare struct {
+	ItemID string  `json:"itemId"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// CardAllocation is what one card paid for on a split-tender order
+type CardAllocation struct {
+	Card      string      `json:"card"`
+	Last4     string      `json:"last4"`
+	Paid      float64     `json:"paid"` // Amount charged to the card, 0 if unknown
+	Items     []ItemShare `json:"items"`
+	ItemTotal float64     `json:"itemTotal"`
+}
+
+// Allocation splits an order's items between the cards that paid for it
+type Allocation struct {
+	OrderID string           `json:"orderId"`
+	Cards   []CardAllocation `json:"cards"`
+}
+
+// AllocateByCard assigns each item of order to the cards that paid for it.
+// assignments maps item IDs to a card's last four digits for items one
+// person paid for outright; every other item is split in proportion to
+// what each card paid beyond its assigned items, or evenly when the
+// per-card amounts are unknown.
+func AllocateByCard(order *walmart.Order, assignments map[string]string) *Allocation {
+	alloc := &Allocation{OrderID: order.ID, Cards: orderCards(order)}
+	if len(alloc.Cards) == 0 {
+		return alloc
+	}
+
+	byLast4 := make(map[string]int, len(alloc.Cards))
+	for i, card := range alloc.Cards {
+		byLast4[card.Last4] = i
+	}
+
+	type line struct {
+		id, name string
+		amount   float64
+	}
+	var shared []line
+	assigned := make([]float64, len(alloc.Cards))
+	for _, item := range order.GetItems() {
+		amount, ok := item.LinePrice()
+		if !ok {
+			continue
+		}
+		id := item.USItemID()
+		if i, ok := byLast4[assignments[id]]; ok {
+			alloc.Cards[i].add(ItemShare{ItemID: id, Name: item.Name(), Amount: amount})
+			assigned[i] += amount
+			continue
+		}
+		shared = append(shared, line{id, item.Name(), amount})
+	}
+
+	weights := make([]float64, len(alloc.Cards))
+	sum := 0.0
+	for i, card := range alloc.Cards {
+		weights[i] = math.Max(card.Paid-assigned[i], 0)
+		sum += weights[i]
+	}
+	for i := range weights {
+		if sum == 0 {
+			weights[i] = 1 / float64(len(weights))
+		} else {
+			weights[i] /= sum
+		}
+	}
+
+	for _, l := range shared {
+		// The last card absorbs rounding so shares add up to the item price
+		remaining := l.amount
+		for i := range alloc.Cards {
+			share := roundCents(l.amount * weights[i])
+			if i == len(alloc.Cards)-1 {
+				share = roundCents(remaining)
+			}
+			remaining -= share
+			if share != 0 {
+				alloc.Cards[i].add(ItemShare{ItemID: l.id, Name: l.name, Amount: share})
+			}
+		}
+	}
+	return alloc
+}
+
+func (c *CardAllocation) add(share ItemShare) {
+	c.Items = append(c.Items, share)
+	c.ItemTotal = roundCents(c.ItemTotal + share.Amount)
+}
+
+// orderCards lists the cards on an order, merging per-group payments
+func orderCards(order *walmart.Order) []CardAllocation {
+	var cards []CardAllocation
+	index := make(map[string]int)
+	for _, charge := range orderCharges(order) {
+		if charge.Last4 == "" || len(charge.Last4) > 4 {
+			continue
+		}
+		if i, ok := index[charge.Last4]; ok {
+			cards[i].Paid += charge.Amount
+			continue
+		}
+		index[charge.Last4] = len(cards)
+		cards = append(cards, CardAllocation{Card: charge.Card, Last4: charge.Last4, Paid: charge.Amount})
+	}
+	if len(cards) > 0 {
+		return cards
+	}
+
+	// Several cards with no per-card amounts
+	for _, method := range order.PaymentMethods {
+		if m := last4Pattern.FindStringSubmatch(method.Description); m != nil {
+			if _, ok := index[m[1]]; !ok {
+				index[m[1]] = len(cards)
+				cards = append(cards, CardAllocation{Card: method.Description, Last4: m[1]})
+			}
+		}
+	}
+	return cards
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// WriteAllocationsCSV renders one row per item share, so each card owner
+// can filter their part
+func WriteAllocationsCSV(w io.Writer, allocations []*Allocation) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"order_id", "card", "last4", "item_id", "item", "amount"})
+	for _, alloc := range allocations {
+		for _, card := range alloc.Cards {
+			for _, item := range card.Items {
+				_ = cw.Write([]string{
+					alloc.OrderID,
+					card.Card,
+					card.Last4,
+					item.ItemID,
+					item.Name,
+					fmt.Sprintf("%.2f", item.Amount),
+				})
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
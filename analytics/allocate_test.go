@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestAllocateByCard(t *testing.T) {
+	o := order(60,
+		item("1", "Groceries", 1, 40),
+		item("2", "Headphones", 1, 20),
+	)
+	o.ID = "split"
+	o.Groups[0].PaymentDetails = &walmart.PaymentDetails{PaymentMethods: []walmart.PaymentMethod{
+		{DisplayName: "Visa", Last4Digits: "1234", Amount: &walmart.Money{Value: 45}},
+		{DisplayName: "Mastercard", Last4Digits: "5678", Amount: &walmart.Money{Value: 15}},
+	}}
+
+	// The headphones were bought outright on the Visa; groceries split on what is left
+	alloc := AllocateByCard(o, map[string]string{"2": "1234"})
+	if len(alloc.Cards) != 2 {
+		t.Fatalf("Expected 2 cards, got %+v", alloc.Cards)
+	}
+	visa, mc := alloc.Cards[0], alloc.Cards[1]
+	if visa.ItemTotal != 20+40*25.0/40 || mc.ItemTotal != 15 {
+		t.Errorf("Unexpected split: visa %.2f, mastercard %.2f", visa.ItemTotal, mc.ItemTotal)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAllocationsCSV(&buf, []*Allocation{alloc}); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Errorf("Expected a header and 3 item shares, got:\n%s", buf.String())
+	}
+}
+
+func TestAllocateByCardWithoutAmounts(t *testing.T) {
+	o := order(10, item("1", "Milk", 1, 10))
+	o.PaymentMethods = []walmart.OrderPaymentMethod{
+		{Description: "Visa ending in 1234"},
+		{Description: "Amex ending in 0005"},
+		{Description: "Discover ending in 7777"},
+	}
+
+	alloc := AllocateByCard(o, nil)
+	total := 0.0
+	for _, card := range alloc.Cards {
+		total += card.ItemTotal
+	}
+	if len(alloc.Cards) != 3 || total != 10 {
+		t.Errorf("Expected an even split that adds up to 10, got %+v", alloc.Cards)
+	}
+}
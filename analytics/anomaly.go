@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// AnomalyKind identifies why an order looks out of pattern
+type AnomalyKind string
+
+const (
+	AnomalyLargeTotal       AnomalyKind = "large_total"        // Total far above usual for the fulfillment type
+	AnomalyNewStore         AnomalyKind = "new_store"          // Store never seen before
+	AnomalyNewPaymentMethod AnomalyKind = "new_payment_method" // Payment method never seen before
+)
+
+// Anomaly is one reason an order stands out
+type Anomaly struct {
+	Kind    AnomalyKind `json:"kind"`
+	OrderID string      `json:"orderId"`
+	Detail  string      `json:"detail"`
+	Score   float64     `json:"score,omitempty"` // Z-score for AnomalyLargeTotal
+}
+
+// AnomalyDetector flags unusually large or out-of-pattern orders, e.g. as a
+// cheap fraud or typo guard on shared accounts
+type AnomalyDetector struct {
+	ZThreshold float64       // Z-score that counts as large, defaults to 3
+	MinSamples int           // Orders per fulfillment type before totals are judged, defaults to 5
+	OnAnomaly  func(Anomaly) // Called for every anomaly found
+
+	totals   map[string][]float64
+	stores   map[string]bool
+	payments map[string]bool
+}
+
+// NewAnomalyDetector learns the usual pattern from past orders
+func NewAnomalyDetector(history []*walmart.Order) *AnomalyDetector {
+	d := &AnomalyDetector{
+		totals:   make(map[string][]float64),
+		stores:   make(map[string]bool),
+		payments: make(map[string]bool),
+	}
+	for _, order := range history {
+		d.learn(order)
+	}
+	return d
+}
+
+// Check reports how order deviates from the orders seen so far, then adds
+// it to the pattern
+func (d *AnomalyDetector) Check(order *walmart.Order) []Anomaly {
+	var anomalies []Anomaly
+	report := func(a Anomaly) {
+		a.OrderID = order.ID
+		anomalies = append(anomalies, a)
+		if d.OnAnomaly != nil {
+			d.OnAnomaly(a)
+		}
+	}
+
+	kind := fulfillmentType(order)
+	if total, ok := order.ChargedTotal(); ok {
+		if z, ok := d.zScore(kind, total); ok && z >= d.threshold() {
+			report(Anomaly{
+				Kind:   AnomalyLargeTotal,
+				Detail: fmt.Sprintf("$%.2f is %.1f standard deviations above usual %s orders", total, z, kind),
+				Score:  z,
+			})
+		}
+	}
+
+	// Only flag novelty once there is a pattern to deviate from
+	if len(d.stores) > 0 {
+		for _, store := range orderStores(order) {
+			if !d.stores[store] {
+				report(Anomaly{Kind: AnomalyNewStore, Detail: "first order from " + store})
+			}
+		}
+	}
+	if len(d.payments) > 0 {
+		for _, method := range order.PaymentMethods {
+			if method.Description != "" && !d.payments[method.Description] {
+				report(Anomaly{Kind: AnomalyNewPaymentMethod, Detail: "first order paid with " + method.Description})
+			}
+		}
+	}
+
+	d.learn(order)
+	return anomalies
+}
+
+func (d *AnomalyDetector) learn(order *walmart.Order) {
+	if total, ok := order.ChargedTotal(); ok {
+		kind := fulfillmentType(order)
+		d.totals[kind] = append(d.totals[kind], total)
+	}
+	for _, store := range orderStores(order) {
+		d.stores[store] = true
+	}
+	for _, method := range order.PaymentMethods {
+		if method.Description != "" {
+			d.payments[method.Description] = true
+		}
+	}
+}
+
+// zScore compares total with past totals of the same fulfillment type
+func (d *AnomalyDetector) zScore(kind string, total float64) (float64, bool) {
+	minSamples := d.MinSamples
+	if minSamples == 0 {
+		minSamples = 5
+	}
+	totals := d.totals[kind]
+	if len(totals) < minSamples {
+		return 0, false
+	}
+
+	mean := 0.0
+	for _, t := range totals {
+		mean += t
+	}
+	mean /= float64(len(totals))
+
+	variance := 0.0
+	for _, t := range totals {
+		variance += (t - mean) * (t - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(totals)))
+	if stddev == 0 {
+		return 0, false
+	}
+	return (total - mean) / stddev, true
+}
+
+func (d *AnomalyDetector) threshold() float64 {
+	if d.ZThreshold == 0 {
+		return 3
+	}
+	return d.ZThreshold
+}
+
+// fulfillmentType groups orders for total comparisons
+func fulfillmentType(order *walmart.Order) string {
+	for _, group := range order.Groups {
+		if group.FulfillmentType != "" {
+			return group.FulfillmentType
+		}
+	}
+	if order.Type != "" {
+		return order.Type
+	}
+	return "UNKNOWN"
+}
+
+func orderStores(order *walmart.Order) []string {
+	var stores []string
+	for _, group := range order.Groups {
+		if group.Store != nil && group.Store.ID != "" {
+			stores = append(stores, group.Store.ID)
+		}
+	}
+	return stores
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import (
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func storeOrder(id string, total float64, storeID, card string) *walmart.Order {
+	o := order(total)
+	o.ID = id
+	o.Groups[0].FulfillmentType = "IN_STORE"
+	o.Groups[0].Store = &walmart.Store{ID: storeID}
+	o.PaymentMethods = []walmart.OrderPaymentMethod{{Description: card}}
+	return o
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	var history []*walmart.Order
+	for i, total := range []float64{40, 45, 50, 55, 60, 42, 58} {
+		history = append(history, storeOrder(string(rune('a'+i)), total, "1234", "Visa ending in 0000"))
+	}
+
+	var events []Anomaly
+	detector := NewAnomalyDetector(history)
+	detector.OnAnomaly = func(a Anomaly) { events = append(events, a) }
+
+	if anomalies := detector.Check(storeOrder("usual", 52, "1234", "Visa ending in 0000")); len(anomalies) != 0 {
+		t.Errorf("Expected a usual order to pass, got %+v", anomalies)
+	}
+
+	anomalies := detector.Check(storeOrder("odd", 900, "9999", "Mastercard ending in 1111"))
+	kinds := make(map[AnomalyKind]bool)
+	for _, a := range anomalies {
+		kinds[a.Kind] = true
+		if a.OrderID != "odd" {
+			t.Errorf("Anomaly missing order ID: %+v", a)
+		}
+	}
+	if !kinds[AnomalyLargeTotal] || !kinds[AnomalyNewStore] || !kinds[AnomalyNewPaymentMethod] {
+		t.Errorf("Expected all three anomalies, got %+v", anomalies)
+	}
+	if len(events) != len(anomalies) {
+		t.Errorf("Expected every anomaly to be emitted, got %d events", len(events))
+	}
+
+	// Once seen, the store and card are part of the pattern
+	for _, a := range detector.Check(storeOrder("again", 50, "9999", "Mastercard ending in 1111")) {
+		if a.Kind != AnomalyLargeTotal {
+			t.Errorf("Unexpected anomaly after learning: %+v", a)
+		}
+	}
+}
+
+func TestAnomalyDetectorNeedsHistory(t *testing.T) {
+	detector := NewAnomalyDetector(nil)
+	if anomalies := detector.Check(storeOrder("first", 500, "1", "Visa")); len(anomalies) != 0 {
+		t.Errorf("Expected no anomalies without history, got %+v", anomalies)
+	}
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"sort"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// ChartData is the labels/series shape most chart libraries take as-is
+type ChartData struct {
+	Labels []string      `json:"labels"`
+	Series []ChartSeries `json:"series"`
+}
+
+// ChartSeries is one line or bar set, aligned with ChartData.Labels
+type ChartSeries struct {
+	Name string    `json:"name"`
+	Data []float64 `json:"data"`
+}
+
+// TimelineChart plots each order's spend and item count by order date,
+// oldest first. Orders with an unparseable date are skipped.
+func TimelineChart(orders []*walmart.Order) ChartData {
+	type point struct {
+		order *walmart.Order
+		date  string
+		sort  int64
+	}
+	var points []point
+	for _, order := range orders {
+		t, err := order.OrderTime()
+		if err != nil {
+			continue
+		}
+		points = append(points, point{order: order, date: t.Format("2006-01-02"), sort: t.Unix()})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].sort < points[j].sort })
+
+	chart := ChartData{
+		Labels: make([]string, len(points)),
+		Series: []ChartSeries{
+			{Name: "Spend", Data: make([]float64, len(points))},
+			{Name: "Items", Data: make([]float64, len(points))},
+		},
+	}
+	for i, p := range points {
+		chart.Labels[i] = p.date
+		chart.Series[0].Data[i] = orderTotal(p.order)
+		chart.Series[1].Data[i] = float64(p.order.GetItemCount())
+	}
+	return chart
+}
+
+// SpendChart plots monthly spend, with an inflation-adjusted series when
+// the months have been passed through AdjustForInflation
+func SpendChart(months []MonthSpend) ChartData {
+	chart := ChartData{Labels: make([]string, len(months))}
+	nominal := ChartSeries{Name: "Spend", Data: make([]float64, len(months))}
+	adjusted := ChartSeries{Name: "Spend (inflation-adjusted)", Data: make([]float64, len(months))}
+
+	isAdjusted := false
+	for i, m := range months {
+		chart.Labels[i] = m.Month.Format("2006-01")
+		nominal.Data[i] = m.Nominal
+		adjusted.Data[i] = m.Real
+		if m.Real != m.Nominal {
+			isAdjusted = true
+		}
+	}
+
+	chart.Series = append(chart.Series, nominal)
+	if isAdjusted {
+		chart.Series = append(chart.Series, adjusted)
+	}
+	return chart
+}
+
+// ComparisonChart plots per-item spend for both periods of a comparison
+func ComparisonChart(c *PeriodComparison) ChartData {
+	var deltas []ItemDelta
+	deltas = append(deltas, c.Changed...)
+	deltas = append(deltas, c.NewItems...)
+	deltas = append(deltas, c.DroppedItems...)
+
+	chart := ChartData{
+		Labels: make([]string, len(deltas)),
+		Series: []ChartSeries{
+			{Name: c.LabelA, Data: make([]float64, len(deltas))},
+			{Name: c.LabelB, Data: make([]float64, len(deltas))},
+		},
+	}
+	for i, d := range deltas {
+		chart.Labels[i] = d.Name
+		chart.Series[0].Data[i] = d.SpendA
+		chart.Series[1].Data[i] = d.SpendB
+	}
+	return chart
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestTimelineChart(t *testing.T) {
+	later := order(20, item("1", "Milk", 1, 4))
+	later.OrderDate = "2024-03-10T12:00:00.000-0700"
+	later.Groups[0].ItemCount = 1
+	earlier := order(10, item("2", "Bread", 2, 6))
+	earlier.OrderDate = "2024-03-01T12:00:00.000-0700"
+	earlier.Groups[0].ItemCount = 2
+	undated := order(99)
+
+	chart := TimelineChart([]*walmart.Order{later, undated, earlier})
+
+	data, _ := json.Marshal(chart)
+	expected := `{"labels":["2024-03-01","2024-03-10"],"series":[{"name":"Spend","data":[10,20]},{"name":"Items","data":[2,1]}]}`
+	if string(data) != expected {
+		t.Errorf("Unexpected chart JSON:\n%s", data)
+	}
+}
+
+func TestSpendChart(t *testing.T) {
+	months := []MonthSpend{
+		{Month: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), Nominal: 100, Real: 100},
+		{Month: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Nominal: 120, Real: 120},
+	}
+
+	chart := SpendChart(months)
+	if len(chart.Series) != 1 || chart.Labels[1] != "2024-02" {
+		t.Errorf("Expected a single nominal series, got %+v", chart)
+	}
+
+	months[0].Real = 110
+	if chart := SpendChart(months); len(chart.Series) != 2 {
+		t.Errorf("Expected an adjusted series, got %+v", chart.Series)
+	}
+}
+
+func TestComparisonChart(t *testing.T) {
+	result := ComparePeriods(
+		Period{Label: "A", Orders: []*walmart.Order{order(4, item("1", "Milk", 1, 4))}},
+		Period{Label: "B", Orders: []*walmart.Order{order(8, item("1", "Milk", 2, 8))}},
+	)
+
+	chart := ComparisonChart(result)
+	if len(chart.Labels) != 1 || chart.Labels[0] != "Milk" || chart.Series[1].Data[0] != 8 {
+		t.Errorf("Unexpected comparison chart: %+v", chart)
+	}
+}
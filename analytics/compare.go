@@ -0,0 +1,205 @@
+// Package analytics computes spend reports over fetched Walmart orders.
+package analytics
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// Period is a labeled set of orders, e.g. "September 2025"
+type Period struct {
+	Label  string
+	Orders []*walmart.Order
+}
+
+// ItemDelta compares one product's purchases across two periods
+type ItemDelta struct {
+	ItemID    string  `json:"itemId"`
+	Name      string  `json:"name"`
+	QuantityA float64 `json:"quantityA"`
+	QuantityB float64 `json:"quantityB"`
+	SpendA    float64 `json:"spendA"`
+	SpendB    float64 `json:"spendB"`
+}
+
+// SpendDelta is the change in spend from period A to period B
+func (d ItemDelta) SpendDelta() float64 {
+	return d.SpendB - d.SpendA
+}
+
+// PeriodComparison is the result of ComparePeriods
+type PeriodComparison struct {
+	LabelA       string      `json:"labelA"`
+	LabelB       string      `json:"labelB"`
+	OrdersA      int         `json:"ordersA"`
+	OrdersB      int         `json:"ordersB"`
+	TotalA       float64     `json:"totalA"`
+	TotalB       float64     `json:"totalB"`
+	Changed      []ItemDelta `json:"changed"` // Bought in both periods, largest spend change first
+	NewItems     []ItemDelta `json:"newItems"`
+	DroppedItems []ItemDelta `json:"droppedItems"`
+}
+
+// ComparePeriods reports per-item spend deltas plus new and dropped items
+// between period a (earlier) and period b (later). Items are matched by
+// Walmart item ID, falling back to the product name.
+func ComparePeriods(a, b Period) *PeriodComparison {
+	result := &PeriodComparison{
+		LabelA:  a.Label,
+		LabelB:  b.Label,
+		OrdersA: len(a.Orders),
+		OrdersB: len(b.Orders),
+		TotalA:  sumOrders(a.Orders),
+		TotalB:  sumOrders(b.Orders),
+	}
+
+	items := make(map[string]*ItemDelta)
+	collect := func(orders []*walmart.Order, inA bool) {
+		for _, order := range orders {
+			for _, item := range order.GetItems() {
+				// Deposits and bag fees are regional charges, not purchases
+				if item.ChargeKind() != "" {
+					continue
+				}
+				key, name := itemKey(item)
+				delta, ok := items[key]
+				if !ok {
+					delta = &ItemDelta{ItemID: key, Name: name}
+					items[key] = delta
+				}
+
+				spend, _ := item.LinePrice()
+				if inA {
+					delta.QuantityA += item.Quantity
+					delta.SpendA += spend
+				} else {
+					delta.QuantityB += item.Quantity
+					delta.SpendB += spend
+				}
+			}
+		}
+	}
+	collect(a.Orders, true)
+	collect(b.Orders, false)
+
+	for _, delta := range items {
+		inA := delta.QuantityA > 0 || delta.SpendA != 0
+		inB := delta.QuantityB > 0 || delta.SpendB != 0
+		switch {
+		case inA && inB:
+			result.Changed = append(result.Changed, *delta)
+		case inB:
+			result.NewItems = append(result.NewItems, *delta)
+		case inA:
+			result.DroppedItems = append(result.DroppedItems, *delta)
+		}
+	}
+
+	sortByDelta(result.Changed)
+	sortByDelta(result.NewItems)
+	sortByDelta(result.DroppedItems)
+	return result
+}
+
+func sumOrders(orders []*walmart.Order) float64 {
+	total := 0.0
+	for _, order := range orders {
+		total += orderTotal(order)
+	}
+	return total
+}
+
+// orderTotal prefers the charged total and falls back to summing items
+func orderTotal(order *walmart.Order) float64 {
+	if total, ok := order.ChargedTotal(); ok {
+		return total
+	}
+	return order.CalculateOrderTotal()
+}
+
+// receiptTotal is orderTotal for receipts from any source
+func receiptTotal(receipt walmart.Receipt) float64 {
+	if order, ok := receipt.(*walmart.Order); ok {
+		return orderTotal(order)
+	}
+	total, _ := receipt.ChargedTotal()
+	return total
+}
+
+func itemKey(item walmart.OrderItem) (key, name string) {
+	if item.ProductInfo == nil {
+		return item.ID, item.ID
+	}
+	if id := item.USItemID(); id != "" {
+		return id, item.Name()
+	}
+	return item.Name(), item.Name()
+}
+
+func sortByDelta(deltas []ItemDelta) {
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := math.Abs(deltas[i].SpendDelta()), math.Abs(deltas[j].SpendDelta())
+		if di != dj {
+			return di > dj
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+}
+
+// WriteText renders the comparison as an aligned plain-text report
+func (c *PeriodComparison) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "=== %s vs %s ===\n", c.LabelB, c.LabelA)
+	fmt.Fprintf(tw, "Orders:\t%d\t(was %d)\n", c.OrdersB, c.OrdersA)
+	fmt.Fprintf(tw, "Spend:\t$%.2f\t(was $%.2f, %+.2f)\n", c.TotalB, c.TotalA, c.TotalB-c.TotalA)
+
+	sections := []struct {
+		title  string
+		deltas []ItemDelta
+	}{
+		{"Changed", c.Changed},
+		{"New", c.NewItems},
+		{"Dropped", c.DroppedItems},
+	}
+	for _, section := range sections {
+		if len(section.deltas) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s items:\n", section.title)
+		for _, d := range section.deltas {
+			fmt.Fprintf(tw, "  %s\t$%.2f\t-> $%.2f\t(%+.2f)\n", d.Name, d.SpendA, d.SpendB, d.SpendDelta())
+		}
+	}
+
+	return tw.Flush()
+}
+
+var compareHTML = template.Must(template.New("compare").Funcs(template.FuncMap{
+	"money": func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"delta": func(v float64) string { return fmt.Sprintf("%+.2f", v) },
+}).Parse(`<h2>{{.LabelB}} vs {{.LabelA}}</h2>
+<p>Orders: {{.OrdersB}} (was {{.OrdersA}})<br>
+Spend: {{money .TotalB}} (was {{money .TotalA}})</p>
+{{define "rows"}}<table>
+<tr><th>Item</th><th>Before</th><th>After</th><th>Change</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{money .SpendA}}</td><td>{{money .SpendB}}</td><td>{{delta .SpendDelta}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Changed}}<h3>Changed items</h3>
+{{template "rows" .Changed}}{{end}}
+{{if .NewItems}}<h3>New items</h3>
+{{template "rows" .NewItems}}{{end}}
+{{if .DroppedItems}}<h3>Dropped items</h3>
+{{template "rows" .DroppedItems}}{{end}}
+`))
+
+// WriteHTML renders the comparison as an HTML fragment
+func (c *PeriodComparison) WriteHTML(w io.Writer) error {
+	return compareHTML.Execute(w, c)
+}
@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func item(id, name string, qty, price float64) walmart.OrderItem {
+	return walmart.OrderItem{
+		Quantity:    qty,
+		ProductInfo: &walmart.ProductInfo{Name: name, USItemID: id},
+		PriceInfo:   &walmart.ItemPrice{LinePrice: &walmart.Price{Value: price}},
+	}
+}
+
+func order(total float64, items ...walmart.OrderItem) *walmart.Order {
+	return &walmart.Order{
+		Groups:       []walmart.OrderGroup{{Items: items}},
+		PriceDetails: &walmart.OrderPriceDetails{GrandTotal: &walmart.PriceLineItem{Value: total}},
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	august := Period{Label: "August", Orders: []*walmart.Order{
+		order(10, item("1", "Milk", 1, 4), item("2", "Bread", 1, 3)),
+	}}
+	september := Period{Label: "September", Orders: []*walmart.Order{
+		order(8, item("1", "Milk", 2, 8)),
+		order(5, item("3", "Eggs", 1, 5)),
+	}}
+
+	result := ComparePeriods(august, september)
+
+	if result.TotalA != 10 || result.TotalB != 13 {
+		t.Errorf("Unexpected totals: %.2f / %.2f", result.TotalA, result.TotalB)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].SpendDelta() != 4 {
+		t.Errorf("Expected Milk to change by +4, got %+v", result.Changed)
+	}
+	if len(result.NewItems) != 1 || result.NewItems[0].Name != "Eggs" {
+		t.Errorf("Expected Eggs to be new, got %+v", result.NewItems)
+	}
+	if len(result.DroppedItems) != 1 || result.DroppedItems[0].Name != "Bread" {
+		t.Errorf("Expected Bread to be dropped, got %+v", result.DroppedItems)
+	}
+
+	var text bytes.Buffer
+	if err := result.WriteText(&text); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "September vs August") {
+		t.Errorf("Unexpected text report:\n%s", text.String())
+	}
+
+	var html bytes.Buffer
+	if err := result.WriteHTML(&html); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	if !strings.Contains(html.String(), "<td>Eggs</td>") {
+		t.Errorf("Unexpected HTML report:\n%s", html.String())
+	}
+}
+
+func TestComparePeriodsSkipsRegionalCharges(t *testing.T) {
+	a := Period{Label: "A", Orders: []*walmart.Order{order(5, item("1", "Soda", 1, 4), item("", "CA CRV", 1, 0.60))}}
+	b := Period{Label: "B", Orders: []*walmart.Order{order(5, item("1", "Soda", 1, 4))}}
+
+	result := ComparePeriods(a, b)
+	if len(result.DroppedItems) != 0 {
+		t.Errorf("Deposits should not show up as dropped items: %+v", result.DroppedItems)
+	}
+}
@@ -0,0 +1,212 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// DigestSource is the part of the client the digest reads from
+type DigestSource interface {
+	GetPurchaseHistory(req walmart.PurchaseHistoryRequest) (*walmart.PurchaseHistoryResponse, error)
+	GetOrder(orderID string, isInStore bool) (*walmart.Order, error)
+}
+
+// DigestSink delivers a rendered digest, e.g. by email or chat webhook
+type DigestSink interface {
+	Send(subject, body string) error
+}
+
+// DigestSinkFunc adapts a function into a DigestSink
+type DigestSinkFunc func(subject, body string) error
+
+// Send calls f
+func (f DigestSinkFunc) Send(subject, body string) error {
+	return f(subject, body)
+}
+
+// TopItem is one of the most purchased items in the digest window
+type TopItem struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Spend    float64 `json:"spend"`
+}
+
+// Digest summarizes one week of orders
+type Digest struct {
+	Start    time.Time              `json:"start"`
+	End      time.Time              `json:"end"`
+	Orders   []*walmart.Order       `json:"orders"`
+	Spend    float64                `json:"spend"`
+	TopItems []TopItem              `json:"topItems"`
+	Upcoming []walmart.OrderSummary `json:"upcoming"` // Orders still in progress
+}
+
+// DigestRunner fetches the past week, computes the digest and dispatches it
+type DigestRunner struct {
+	Source   DigestSource
+	Sinks    []DigestSink
+	Window   time.Duration    // Defaults to 7 days
+	TopN     int              // Defaults to 5
+	MaxPages int              // History pages to scan, defaults to 5
+	Now      func() time.Time // Defaults to time.Now
+}
+
+// Run builds the digest and sends it to every sink, returning the first
+// sink error after attempting all of them
+func (r *DigestRunner) Run() error {
+	digest, err := r.Build()
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Walmart weekly digest: %s - %s",
+		digest.Start.Format("Jan 2"), digest.End.Format("Jan 2"))
+	body := digest.Render()
+
+	var firstErr error
+	for _, sink := range r.Sinks {
+		if err := sink.Send(subject, body); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to send digest: %w", err)
+		}
+	}
+	return firstErr
+}
+
+// Build pages through recent history, newest first, fetching full orders
+// until it reaches orders older than the window
+func (r *DigestRunner) Build() (*Digest, error) {
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	window := r.Window
+	if window == 0 {
+		window = 7 * 24 * time.Hour
+	}
+	maxPages := r.MaxPages
+	if maxPages == 0 {
+		maxPages = 5
+	}
+
+	digest := &Digest{End: now()}
+	digest.Start = digest.End.Add(-window)
+
+	// History lists an order once per fulfillment group
+	seen := make(map[string]bool)
+	cursor := ""
+	for page := 0; page < maxPages; page++ {
+		resp, err := r.Source.GetPurchaseHistory(walmart.PurchaseHistoryRequest{Cursor: cursor, Limit: 20})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch history: %w", err)
+		}
+
+		reachedOlder := false
+		for _, summary := range resp.Data.OrderHistoryV2.OrderGroups {
+			if summary.IsActive {
+				digest.Upcoming = append(digest.Upcoming, summary)
+			}
+			if seen[summary.OrderID] {
+				continue
+			}
+			seen[summary.OrderID] = true
+
+			order, err := r.Source.GetOrder(summary.OrderID, summary.FulfillmentType == "IN_STORE")
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch order %s: %w", summary.OrderID, err)
+			}
+
+			placed, err := order.OrderTime()
+			if err != nil {
+				continue
+			}
+			if placed.Before(digest.Start) {
+				reachedOlder = true
+				break
+			}
+			if !placed.After(digest.End) {
+				digest.Orders = append(digest.Orders, order)
+				digest.Spend += orderTotal(order)
+			}
+		}
+
+		cursor = resp.Data.OrderHistoryV2.PageInfo.NextPageCursor
+		if reachedOlder || cursor == "" {
+			break
+		}
+	}
+
+	topN := r.TopN
+	if topN == 0 {
+		topN = 5
+	}
+	digest.TopItems = topItems(digest.Orders, topN)
+	return digest, nil
+}
+
+func topItems(orders []*walmart.Order, n int) []TopItem {
+	byKey := make(map[string]*TopItem)
+	for _, order := range orders {
+		for _, item := range order.GetItems() {
+			if item.ChargeKind() != "" {
+				continue // Deposits and bag fees aren't products
+			}
+			key, name := itemKey(item)
+			top, ok := byKey[key]
+			if !ok {
+				top = &TopItem{Name: name}
+				byKey[key] = top
+			}
+			top.Quantity += item.Quantity
+			spend, _ := item.LinePrice()
+			top.Spend += spend
+		}
+	}
+
+	items := make([]TopItem, 0, len(byKey))
+	for _, top := range byKey {
+		items = append(items, *top)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Spend != items[j].Spend {
+			return items[i].Spend > items[j].Spend
+		}
+		return items[i].Name < items[j].Name
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// Render formats the digest as plain text
+func (d *Digest) Render() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "=== Week of %s - %s ===\n", d.Start.Format("Jan 2"), d.End.Format("Jan 2, 2006"))
+	fmt.Fprintf(&b, "Orders: %d\n", len(d.Orders))
+	fmt.Fprintf(&b, "Spend:  $%.2f\n", d.Spend)
+
+	if len(d.TopItems) > 0 {
+		b.WriteString("\nTop items:\n")
+		for i, item := range d.TopItems {
+			fmt.Fprintf(&b, "  %d. %s (qty: %g) $%.2f\n", i+1, item.Name, item.Quantity, item.Spend)
+		}
+	}
+
+	if len(d.Upcoming) > 0 {
+		b.WriteString("\nUpcoming:\n")
+		for _, order := range d.Upcoming {
+			message := order.DeliveryMessage
+			if message == "" && order.Status != nil {
+				message = order.Status.StatusType
+			}
+			fmt.Fprintf(&b, "  Order %s - %s\n", order.OrderID, message)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+type fakeSource struct {
+	history []walmart.OrderSummary
+	orders  map[string]*walmart.Order
+}
+
+func (f *fakeSource) GetPurchaseHistory(req walmart.PurchaseHistoryRequest) (*walmart.PurchaseHistoryResponse, error) {
+	resp := &walmart.PurchaseHistoryResponse{}
+	resp.Data.OrderHistoryV2.OrderGroups = f.history
+	return resp, nil
+}
+
+func (f *fakeSource) GetOrder(orderID string, isInStore bool) (*walmart.Order, error) {
+	if order, ok := f.orders[orderID]; ok {
+		return order, nil
+	}
+	return nil, fmt.Errorf("order %s not found", orderID)
+}
+
+func TestDigestRunner(t *testing.T) {
+	now := time.Date(2025, 9, 14, 12, 0, 0, 0, time.UTC)
+	recent := order(12, item("1", "Milk", 2, 8), item("2", "Bread", 1, 4))
+	recent.OrderDate = "2025-09-12T10:00:00.000-0000"
+	pending := order(30, item("3", "Detergent", 1, 30))
+	pending.OrderDate = "2025-09-13T10:00:00.000-0000"
+	old := order(99, item("4", "TV", 1, 99))
+	old.OrderDate = "2025-08-01T10:00:00.000-0000"
+
+	source := &fakeSource{
+		history: []walmart.OrderSummary{
+			{OrderID: "pending", IsActive: true, DeliveryMessage: "Arrives Monday"},
+			{OrderID: "recent", FulfillmentType: "IN_STORE"},
+			{OrderID: "old"},
+		},
+		orders: map[string]*walmart.Order{"recent": recent, "pending": pending, "old": old},
+	}
+
+	var subject, body string
+	runner := &DigestRunner{
+		Source: source,
+		Sinks: []DigestSink{DigestSinkFunc(func(s, b string) error {
+			subject, body = s, b
+			return nil
+		})},
+		Now: func() time.Time { return now },
+	}
+
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !strings.Contains(subject, "Sep 7 - Sep 14") {
+		t.Errorf("Unexpected subject: %s", subject)
+	}
+	for _, want := range []string{"Orders: 2", "Spend:  $42.00", "1. Detergent", "Arrives Monday"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in digest:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "TV") {
+		t.Errorf("Orders outside the window should be excluded:\n%s", body)
+	}
+}
+
+func TestDigestCountsSplitOrdersOnce(t *testing.T) {
+	now := time.Date(2025, 9, 14, 12, 0, 0, 0, time.UTC)
+	split := order(20, item("1", "Milk", 1, 4), item("2", "Paper Towels", 1, 15), item("", "Checkout Bag Fee", 1, 1))
+	split.OrderDate = "2025-09-12T10:00:00.000-0000"
+
+	fetches := 0
+	source := &countingSource{fakeSource: fakeSource{
+		// One order shipped in two groups shows up twice in history
+		history: []walmart.OrderSummary{{OrderID: "split"}, {OrderID: "split"}},
+		orders:  map[string]*walmart.Order{"split": split},
+	}, fetches: &fetches}
+
+	digest, err := (&DigestRunner{Source: source, Now: func() time.Time { return now }}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if fetches != 1 || len(digest.Orders) != 1 || digest.Spend != 20 {
+		t.Errorf("Expected the order once, got %d fetches, %d orders, $%.2f", fetches, len(digest.Orders), digest.Spend)
+	}
+	for _, top := range digest.TopItems {
+		if top.Name == "Checkout Bag Fee" {
+			t.Errorf("Bag fees should not rank as items: %+v", digest.TopItems)
+		}
+	}
+}
+
+type countingSource struct {
+	fakeSource
+	fetches *int
+}
+
+func (c *countingSource) GetOrder(orderID string, isInStore bool) (*walmart.Order, error) {
+	*c.fetches++
+	return c.fakeSource.GetOrder(orderID, isInStore)
+}
@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// CPISeries maps a period ("2024" or "2024-03") to a price index value.
+// Monthly entries take precedence over annual ones.
+type CPISeries map[string]float64
+
+// DefaultCPI is the US CPI-U annual average (all items, 1982-84=100)
+// published by the Bureau of Labor Statistics
+var DefaultCPI = CPISeries{
+	"2010": 218.056,
+	"2011": 224.939,
+	"2012": 229.594,
+	"2013": 232.957,
+	"2014": 236.736,
+	"2015": 237.017,
+	"2016": 240.007,
+	"2017": 245.120,
+	"2018": 251.107,
+	"2019": 255.657,
+	"2020": 258.811,
+	"2021": 270.970,
+	"2022": 292.655,
+	"2023": 304.702,
+	"2024": 313.689,
+}
+
+// Index returns the index for t's month, its year, or the latest year on
+// record when t is newer than the series
+func (s CPISeries) Index(t time.Time) (float64, bool) {
+	if v, ok := s[t.Format("2006-01")]; ok {
+		return v, true
+	}
+	if v, ok := s[t.Format("2006")]; ok {
+		return v, true
+	}
+
+	latest := ""
+	for period := range s {
+		if len(period) == 4 && period > latest {
+			latest = period
+		}
+	}
+	if latest != "" && t.Format("2006") > latest {
+		return s[latest], true
+	}
+	return 0, false
+}
+
+// Adjust converts an amount spent at time from into the money of time to
+func (s CPISeries) Adjust(amount float64, from, to time.Time) (float64, error) {
+	fromIndex, ok := s.Index(from)
+	if !ok {
+		return 0, fmt.Errorf("no CPI value for %s", from.Format("2006-01"))
+	}
+	toIndex, ok := s.Index(to)
+	if !ok {
+		return 0, fmt.Errorf("no CPI value for %s", to.Format("2006-01"))
+	}
+	return amount * toIndex / fromIndex, nil
+}
+
+// LoadCPISeriesCSV reads "period,value" rows, e.g. a BLS export trimmed to
+// two columns. A header row is skipped.
+func LoadCPISeriesCSV(r io.Reader) (CPISeries, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPI series: %w", err)
+	}
+
+	series := make(CPISeries)
+	for i, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid CPI value on line %d: %w", i+1, err)
+		}
+		series[strings.TrimSpace(record[0])] = value
+	}
+	return series, nil
+}
+
+// MonthSpend is one month of spend in nominal and inflation-adjusted terms
+type MonthSpend struct {
+	Month   time.Time `json:"month"`
+	Orders  int       `json:"orders"`
+	Nominal float64   `json:"nominal"`
+	Real    float64   `json:"real"` // In dollars of the report's base month
+}
+
+// MonthlySpend totals orders per calendar month, oldest first. Orders with
+// an unparseable date are skipped.
+func MonthlySpend(orders []*walmart.Order) []MonthSpend {
+	return MonthlyReceiptSpend(walmart.OrdersAsReceipts(orders))
+}
+
+// MonthlyReceiptSpend is MonthlySpend for receipts from any source
+func MonthlyReceiptSpend(receipts []walmart.Receipt) []MonthSpend {
+	months := make(map[time.Time]*MonthSpend)
+	for _, receipt := range receipts {
+		t, err := receipt.OrderTime()
+		if err != nil {
+			continue
+		}
+		month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		entry, ok := months[month]
+		if !ok {
+			entry = &MonthSpend{Month: month}
+			months[month] = entry
+		}
+		entry.Orders++
+		entry.Nominal += receiptTotal(receipt)
+	}
+
+	result := make([]MonthSpend, 0, len(months))
+	for _, entry := range months {
+		entry.Real = entry.Nominal
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Month.Before(result[j].Month) })
+	return result
+}
+
+// AdjustForInflation fills Real for each month in dollars of base
+func AdjustForInflation(months []MonthSpend, series CPISeries, base time.Time) ([]MonthSpend, error) {
+	adjusted := make([]MonthSpend, len(months))
+	for i, month := range months {
+		value, err := series.Adjust(month.Nominal, month.Month, base)
+		if err != nil {
+			return nil, err
+		}
+		month.Real = value
+		adjusted[i] = month
+	}
+	return adjusted, nil
+}
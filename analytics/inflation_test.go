@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestCPISeriesAdjust(t *testing.T) {
+	from := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := DefaultCPI.Adjust(100, from, to)
+	if err != nil {
+		t.Fatalf("Adjust failed: %v", err)
+	}
+	want := 100 * 313.689 / 258.811
+	if math.Abs(got-want) > 0.001 {
+		t.Errorf("Expected %.3f, got %.3f", want, got)
+	}
+
+	// Monthly entries override the annual value
+	series, err := LoadCPISeriesCSV(strings.NewReader("period,value\n2020,100\n2020-06,110\n2024,121\n"))
+	if err != nil {
+		t.Fatalf("Failed to load CSV: %v", err)
+	}
+	got, _ = series.Adjust(110, from, to)
+	if math.Abs(got-121) > 0.001 {
+		t.Errorf("Expected 121, got %.3f", got)
+	}
+
+	if _, err := DefaultCPI.Adjust(1, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), to); err == nil {
+		t.Error("Expected an error for dates before the series")
+	}
+}
+
+func TestMonthlySpend(t *testing.T) {
+	orders := []*walmart.Order{
+		{OrderDate: "2023-02-10T10:00:00.000-0700", PriceDetails: &walmart.OrderPriceDetails{GrandTotal: &walmart.PriceLineItem{Value: 20}}},
+		{OrderDate: "2023-02-20T10:00:00.000-0700", PriceDetails: &walmart.OrderPriceDetails{GrandTotal: &walmart.PriceLineItem{Value: 30}}},
+		{OrderDate: "2022-12-01T10:00:00.000-0700", PriceDetails: &walmart.OrderPriceDetails{GrandTotal: &walmart.PriceLineItem{Value: 10}}},
+		{OrderDate: "garbage"},
+	}
+
+	months := MonthlySpend(orders)
+	if len(months) != 2 {
+		t.Fatalf("Expected 2 months, got %d", len(months))
+	}
+	if months[0].Month.Year() != 2022 || months[1].Nominal != 50 || months[1].Orders != 2 {
+		t.Errorf("Unexpected months: %+v", months)
+	}
+
+	adjusted, err := AdjustForInflation(months, DefaultCPI, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("AdjustForInflation failed: %v", err)
+	}
+	if adjusted[0].Real <= adjusted[0].Nominal {
+		t.Errorf("Real 2022 spend in 2024 dollars should exceed nominal: %+v", adjusted[0])
+	}
+}
+
+func TestMonthlyReceiptSpendMixesSources(t *testing.T) {
+	apiOrder := order(20)
+	apiOrder.OrderDate = "2024-03-10T12:00:00.000-0700"
+	emailed := &walmart.BasicReceipt{
+		Source: "email",
+		Time:   time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC),
+		Lines:  []walmart.ReceiptLine{{Name: "Milk", Amount: 5}},
+	}
+
+	months := MonthlyReceiptSpend([]walmart.Receipt{apiOrder, emailed})
+	if len(months) != 1 || months[0].Orders != 2 || months[0].Nominal != 25 {
+		t.Errorf("Unexpected months: %+v", months)
+	}
+}
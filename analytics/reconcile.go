@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// DefaultPostingLag is how long a Walmart charge typically takes to post
+const DefaultPostingLag = 2 * 24 * time.Hour
+
+// orderURLPrefix links worksheet rows back to the order page
+const orderURLPrefix = "https://www.walmart.com/orders/"
+
+// last4Pattern pulls the card number suffix out of "Visa ending in 1234"
+var last4Pattern = regexp.MustCompile(`(\d{4})\s*$`)
+
+// ExpectedCharge is one card charge a statement should show
+type ExpectedCharge struct {
+	OrderID   string    `json:"orderId"`
+	OrderDate time.Time `json:"orderDate"`
+	PostDate  time.Time `json:"postDate"` // Approximate: order date plus the posting lag
+	Amount    float64   `json:"amount"`
+	Card      string    `json:"card"`
+	Last4     string    `json:"last4"`
+	OrderURL  string    `json:"orderUrl"`
+
+	Tender   walmart.TenderKind `json:"tender"`
+	Provider string             `json:"provider,omitempty"` // Match against this provider's transactions, not a card charge
+	InStore  bool               `json:"inStore"`
+
+	Reward     float64 `json:"reward,omitempty"`     // Estimated rewards, filled by AnnotateRewards
+	RewardRule string  `json:"rewardRule,omitempty"` // Name of the rule that matched
+}
+
+// Worksheet lists the card charges expected to post in one month
+type Worksheet struct {
+	Month   time.Time        `json:"month"`
+	Charges []ExpectedCharge `json:"charges"`
+	Total   float64          `json:"total"`
+	Rewards float64          `json:"rewards,omitempty"`
+}
+
+// ReconciliationWorksheet collects the charges from orders expected to post
+// in month, splitting orders paid with several cards when the per-card
+// amounts are known. A zero postingLag uses DefaultPostingLag.
+func ReconciliationWorksheet(orders []*walmart.Order, month time.Time, postingLag time.Duration) *Worksheet {
+	if postingLag == 0 {
+		postingLag = DefaultPostingLag
+	}
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0)
+
+	sheet := &Worksheet{Month: start}
+	for _, order := range orders {
+		orderTime, err := order.OrderTime()
+		if err != nil {
+			continue
+		}
+		postDate := orderTime.Add(postingLag)
+		if postDate.Before(start) || !postDate.Before(end) {
+			continue
+		}
+
+		for _, charge := range orderCharges(order) {
+			charge.OrderID = order.ID
+			charge.OrderDate = orderTime
+			charge.PostDate = postDate
+			charge.OrderURL = orderURLPrefix + order.ID
+			charge.InStore = order.Type == "IN_STORE"
+			sheet.Charges = append(sheet.Charges, charge)
+			sheet.Total += charge.Amount
+		}
+	}
+
+	sort.SliceStable(sheet.Charges, func(i, j int) bool {
+		return sheet.Charges[i].PostDate.Before(sheet.Charges[j].PostDate)
+	})
+	return sheet
+}
+
+// orderCharges splits an order into per-card charges
+func orderCharges(order *walmart.Order) []ExpectedCharge {
+	var charges []ExpectedCharge
+	for _, group := range order.Groups {
+		if group.PaymentDetails == nil {
+			continue
+		}
+		for _, method := range group.PaymentDetails.PaymentMethods {
+			if method.Amount == nil {
+				continue
+			}
+			charges = append(charges, ExpectedCharge{
+				Amount:   method.Amount.Value,
+				Card:     method.DisplayName,
+				Last4:    method.Last4Digits,
+				Tender:   method.Tender(),
+				Provider: method.Provider(),
+			})
+		}
+	}
+	if len(charges) > 0 {
+		return charges
+	}
+
+	// Without per-card amounts the whole order lands on the listed cards
+	total, ok := order.ChargedTotal()
+	if !ok {
+		return nil
+	}
+	charge := ExpectedCharge{Amount: total, Tender: walmart.TenderOther}
+	var cards, last4s []string
+	for i, method := range order.PaymentMethods {
+		// Financing takes precedence since the bank sees the provider, not the order
+		if i == 0 || method.Tender() == walmart.TenderFinancing {
+			charge.Tender = method.Tender()
+			charge.Provider = method.Provider()
+		}
+		cards = append(cards, method.Description)
+		if m := last4Pattern.FindStringSubmatch(method.Description); m != nil {
+			last4s = append(last4s, m[1])
+		}
+	}
+	charge.Card = strings.Join(cards, " + ")
+	charge.Last4 = strings.Join(last4s, "/")
+	return []ExpectedCharge{charge}
+}
+
+// WriteCSV renders the worksheet with one row per expected charge
+func (s *Worksheet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"post_date", "order_date", "amount", "card", "last4", "order_id", "order_url", "tender", "provider", "reward"})
+	for _, c := range s.Charges {
+		_ = cw.Write([]string{
+			c.PostDate.Format("2006-01-02"),
+			c.OrderDate.Format("2006-01-02"),
+			fmt.Sprintf("%.2f", c.Amount),
+			c.Card,
+			c.Last4,
+			c.OrderID,
+			c.OrderURL,
+			string(c.Tender),
+			c.Provider,
+			fmt.Sprintf("%.2f", c.Reward),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON renders the worksheet as indented JSON
+func (s *Worksheet) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
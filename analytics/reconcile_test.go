@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestReconciliationWorksheet(t *testing.T) {
+	single := order(25.50)
+	single.ID = "111"
+	single.OrderDate = "2024-03-10T12:00:00.000-0700"
+	single.PaymentMethods = []walmart.OrderPaymentMethod{{Description: "Visa ending in 1234"}}
+
+	split := order(40)
+	split.ID = "222"
+	split.OrderDate = "2024-03-20T09:00:00.000-0700"
+	split.Groups[0].PaymentDetails = &walmart.PaymentDetails{PaymentMethods: []walmart.PaymentMethod{
+		{DisplayName: "Visa", Last4Digits: "1234", Amount: &walmart.Money{Value: 30}},
+		{DisplayName: "Gift Card", Last4Digits: "9999", Amount: &walmart.Money{Value: 10}},
+	}}
+
+	// Posts in April once the lag is applied
+	late := order(12)
+	late.ID = "333"
+	late.OrderDate = "2024-03-30T20:00:00.000-0700"
+
+	month := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sheet := ReconciliationWorksheet([]*walmart.Order{late, split, single}, month, 0)
+
+	if len(sheet.Charges) != 3 {
+		t.Fatalf("Expected 3 charges, got %d", len(sheet.Charges))
+	}
+	if sheet.Total != 65.50 {
+		t.Errorf("Expected total 65.50, got %.2f", sheet.Total)
+	}
+
+	first := sheet.Charges[0]
+	if first.OrderID != "111" || first.Last4 != "1234" || first.Amount != 25.50 {
+		t.Errorf("Unexpected first charge: %+v", first)
+	}
+	if first.OrderURL != "https://www.walmart.com/orders/111" {
+		t.Errorf("Unexpected order URL: %s", first.OrderURL)
+	}
+	if sheet.Charges[2].Card != "Gift Card" || sheet.Charges[2].Amount != 10 {
+		t.Errorf("Expected split payment rows, got %+v", sheet.Charges[2])
+	}
+
+	var buf bytes.Buffer
+	if err := sheet.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "2024-03-12,2024-03-10,25.50,Visa ending in 1234,1234,111") {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := sheet.WriteJSON(&buf); err != nil || !strings.Contains(buf.String(), `"orderUrl"`) {
+		t.Errorf("Unexpected JSON output (%v):\n%s", err, buf.String())
+	}
+}
+
+func TestReconciliationWorksheetFinancing(t *testing.T) {
+	financed := order(300)
+	financed.ID = "444"
+	financed.OrderDate = "2024-03-05T12:00:00.000-0700"
+	financed.PaymentMethods = []walmart.OrderPaymentMethod{{Description: "Affirm", PaymentType: "AFFIRM"}}
+
+	sheet := ReconciliationWorksheet([]*walmart.Order{financed}, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), 0)
+	if len(sheet.Charges) != 1 {
+		t.Fatalf("Expected 1 charge, got %d", len(sheet.Charges))
+	}
+	if charge := sheet.Charges[0]; charge.Tender != walmart.TenderFinancing || charge.Provider != "Affirm" {
+		t.Errorf("Expected financed charge to point at Affirm, got %+v", charge)
+	}
+}
@@ -0,0 +1,47 @@
+package analytics
+
+import "math"
+
+// RewardRule estimates the rewards a card earns on matching charges
+type RewardRule struct {
+	Name  string                    // Shown on annotated charges, e.g. "Walmart Rewards card online"
+	Last4 string                    // Card to match; empty matches every card
+	Rate  float64                   // Fraction of the charge earned, e.g. 0.05 for 5%
+	Match func(ExpectedCharge) bool // Optional extra condition, e.g. OnlineOnly
+}
+
+// OnlineOnly matches charges for Walmart.com orders
+func OnlineOnly(charge ExpectedCharge) bool {
+	return !charge.InStore
+}
+
+// InStoreOnly matches charges for in-store purchases
+func InStoreOnly(charge ExpectedCharge) bool {
+	return charge.InStore
+}
+
+// matches reports whether the rule applies to charge
+func (r RewardRule) matches(charge ExpectedCharge) bool {
+	if r.Last4 != "" && charge.Last4 != r.Last4 {
+		return false
+	}
+	return r.Match == nil || r.Match(charge)
+}
+
+// AnnotateRewards fills in the estimated reward on each charge from the
+// first matching rule and totals them on the worksheet
+func (s *Worksheet) AnnotateRewards(rules ...RewardRule) {
+	s.Rewards = 0
+	for i := range s.Charges {
+		charge := &s.Charges[i]
+		charge.Reward, charge.RewardRule = 0, ""
+		for _, rule := range rules {
+			if rule.matches(*charge) {
+				charge.Reward = math.Round(charge.Amount*rule.Rate*100) / 100
+				charge.RewardRule = rule.Name
+				break
+			}
+		}
+		s.Rewards += charge.Reward
+	}
+}
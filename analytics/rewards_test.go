@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestAnnotateRewards(t *testing.T) {
+	online := order(100)
+	online.ID = "online"
+	online.OrderDate = "2024-03-05T12:00:00.000-0700"
+	online.PaymentMethods = []walmart.OrderPaymentMethod{{Description: "Mastercard ending in 4321"}}
+
+	inStore := order(50)
+	inStore.ID = "store"
+	inStore.Type = "IN_STORE"
+	inStore.OrderDate = "2024-03-06T12:00:00.000-0700"
+	inStore.PaymentMethods = []walmart.OrderPaymentMethod{{Description: "Mastercard ending in 4321"}}
+
+	sheet := ReconciliationWorksheet([]*walmart.Order{online, inStore}, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), 0)
+	sheet.AnnotateRewards(
+		RewardRule{Name: "5% online", Last4: "4321", Rate: 0.05, Match: OnlineOnly},
+		RewardRule{Name: "2% in store", Last4: "4321", Rate: 0.02},
+		RewardRule{Name: "other card", Last4: "0000", Rate: 0.10},
+	)
+
+	if sheet.Charges[0].Reward != 5 || sheet.Charges[0].RewardRule != "5% online" {
+		t.Errorf("Unexpected online reward: %+v", sheet.Charges[0])
+	}
+	if sheet.Charges[1].Reward != 1 || sheet.Charges[1].RewardRule != "2% in store" {
+		t.Errorf("Unexpected in-store reward: %+v", sheet.Charges[1])
+	}
+	if sheet.Rewards != 6 {
+		t.Errorf("Expected 6.00 in rewards, got %.2f", sheet.Rewards)
+	}
+
+	// Re-annotating replaces earlier results
+	sheet.AnnotateRewards()
+	if sheet.Rewards != 0 || sheet.Charges[0].RewardRule != "" {
+		t.Error("Expected rewards to be cleared")
+	}
+}
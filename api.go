@@ -0,0 +1,16 @@
+package walmart
+
+// WalmartAPI is the order and history surface of WalmartClient, for
+// consumers that want to swap in walmarttest.FakeClient in their tests
+type WalmartAPI interface {
+	GetOrder(orderID string, isInStore bool) (*Order, error)
+	GetOrderAutoDetect(orderID string) (*Order, error)
+	GetDeliveryOrderWithTip(orderID string) (*Order, error)
+	GetPurchaseHistory(req PurchaseHistoryRequest) (*PurchaseHistoryResponse, error)
+	GetRecentOrders(limit int) ([]OrderSummary, error)
+	GetAllOrders(maxPages int) ([]OrderSummary, error)
+	SearchOrders(searchTerm string, limit int) ([]OrderSummary, error)
+	GetOrdersByType(orderType string, limit int) ([]OrderSummary, error)
+}
+
+var _ WalmartAPI = (*WalmartClient)(nil)
@@ -0,0 +1,175 @@
+package walmart
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// AuthStrategy is one way of getting the client a working session again
+type AuthStrategy interface {
+	Name() string
+	Refresh(m *AuthManager) error
+}
+
+// AuthEventType identifies a transition in the re-auth chain
+type AuthEventType string
+
+const (
+	AuthEventAttempt   AuthEventType = "attempt"   // Strategy is starting
+	AuthEventRefreshed AuthEventType = "refreshed" // Strategy succeeded and the request will be retried
+	AuthEventFailed    AuthEventType = "failed"    // Strategy failed, or the retry still had no session
+	AuthEventRecovered AuthEventType = "recovered" // Retried request succeeded
+	AuthEventExhausted AuthEventType = "exhausted" // Every strategy was tried without success
+)
+
+// AuthEvent is emitted for every transition so callers can log or alert
+type AuthEvent struct {
+	Type     AuthEventType
+	Strategy string
+	Err      error
+	Time     time.Time
+}
+
+// AuthManager chains refresh strategies in order when a request fails
+// because the session is no longer accepted
+type AuthManager struct {
+	Client     *WalmartClient
+	Strategies []AuthStrategy
+	OnEvent    func(AuthEvent)
+	OTP        OTPProvider // Answers one-time passcode challenges in login strategies
+}
+
+// ErrAuthExhausted is returned when no strategy could restore the session
+var ErrAuthExhausted = errors.New("all re-auth strategies failed")
+
+// NewAuthManager creates a manager that tries strategies in the given order
+func NewAuthManager(client *WalmartClient, strategies ...AuthStrategy) *AuthManager {
+	return &AuthManager{
+		Client:     client,
+		Strategies: strategies,
+	}
+}
+
+// Run calls fn and, while it fails with a session error, works through the
+// strategy chain, retrying fn after each successful refresh
+func (m *AuthManager) Run(fn func() error) error {
+	err := fn()
+	if !isSessionError(err) {
+		return err
+	}
+
+	lastErr := err
+	for _, strategy := range m.Strategies {
+		m.emit(AuthEventAttempt, strategy.Name(), nil)
+
+		if refreshErr := strategy.Refresh(m); refreshErr != nil {
+			m.emit(AuthEventFailed, strategy.Name(), refreshErr)
+			lastErr = refreshErr
+			continue
+		}
+		m.emit(AuthEventRefreshed, strategy.Name(), nil)
+
+		err = fn()
+		if err == nil {
+			m.emit(AuthEventRecovered, strategy.Name(), nil)
+			return nil
+		}
+		if !isSessionError(err) {
+			return err
+		}
+		m.emit(AuthEventFailed, strategy.Name(), err)
+		lastErr = err
+	}
+
+	m.emit(AuthEventExhausted, "", lastErr)
+	return fmt.Errorf("%w: %v", ErrAuthExhausted, lastErr)
+}
+
+func (m *AuthManager) emit(eventType AuthEventType, strategy string, err error) {
+	if m.OnEvent == nil {
+		return
+	}
+	m.OnEvent(AuthEvent{
+		Type:     eventType,
+		Strategy: strategy,
+		Err:      err,
+		Time:     time.Now(),
+	})
+}
+
+// isSessionError reports whether err means the cookies were rejected
+func isSessionError(err error) bool {
+	return errors.Is(err, ErrSessionExpired) || errors.Is(err, ErrBotChallenge)
+}
+
+// Built-in strategies
+
+type authStrategyFunc struct {
+	name string
+	fn   func(m *AuthManager) error
+}
+
+func (s authStrategyFunc) Name() string                 { return s.name }
+func (s authStrategyFunc) Refresh(m *AuthManager) error { return s.fn(m) }
+
+// NewAuthStrategy adapts a function, e.g. a headless browser login, into an
+// AuthStrategy
+func NewAuthStrategy(name string, fn func(m *AuthManager) error) AuthStrategy {
+	return authStrategyFunc{name: name, fn: fn}
+}
+
+// RotationStrategy retries once with whatever cookies the failed response
+// rotated. Rotations persisted by other processes are merged in when they
+// are newer, and the result is saved so the rotation isn't lost.
+func RotationStrategy() AuthStrategy {
+	return NewAuthStrategy("rotation", func(m *AuthManager) error {
+		if err := m.Client.CookieStore.mergeSaved(); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to merge saved cookies: %w", err)
+		}
+		return m.Client.CookieStore.Save()
+	})
+}
+
+// WarmUpStrategy loads the orders page like a browser would, picking up any
+// cookies Walmart rotates on a regular page view
+func WarmUpStrategy() AuthStrategy {
+	return NewAuthStrategy("warm-up", func(m *AuthManager) error {
+		return m.Client.loadPage(m.Client.baseURL + defaultWarmUpPath)
+	})
+}
+
+// ExtensionPushStrategy waits for the browser extension to push fresh cookies
+func ExtensionPushStrategy(receiver *CookiePushReceiver, timeout time.Duration) AuthStrategy {
+	return NewAuthStrategy("extension-push", func(m *AuthManager) error {
+		// Drop notifications for pushes that arrived before the failure
+		select {
+		case <-receiver.Pushed():
+		default:
+		}
+
+		select {
+		case <-receiver.Pushed():
+			return nil
+		case <-time.After(timeout):
+			return fmt.Errorf("no cookie push within %s", timeout)
+		}
+	})
+}
+
+// RelayStrategy serves the cookie relay page and waits for a paste; out
+// receives the QR code, e.g. os.Stdout, and may be nil
+func RelayStrategy(addr string, timeout time.Duration, out io.Writer) AuthStrategy {
+	return NewAuthStrategy("relay", func(m *AuthManager) error {
+		return m.Client.ServeCookieRelay(addr, timeout, out)
+	})
+}
+
+// PromptStrategy asks the user for a fresh curl capture on the terminal
+func PromptStrategy() AuthStrategy {
+	return NewAuthStrategy("prompt", func(m *AuthManager) error {
+		return m.Client.RefreshFromBrowser()
+	})
+}
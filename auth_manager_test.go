@@ -0,0 +1,139 @@
+package walmart
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestAuthManagerChain(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+
+	var tried []string
+	failing := NewAuthStrategy("warm-up", func(m *AuthManager) error {
+		tried = append(tried, "warm-up")
+		return errors.New("warm-up failed")
+	})
+	fixing := NewAuthStrategy("headless", func(m *AuthManager) error {
+		tried = append(tried, "headless")
+		m.Client.CookieStore.Set("CID", &Cookie{Value: "fresh"})
+		return nil
+	})
+
+	var events []AuthEventType
+	manager := NewAuthManager(client, RotationStrategy(), failing, fixing)
+	manager.OnEvent = func(e AuthEvent) { events = append(events, e.Type) }
+
+	calls := 0
+	err := manager.Run(func() error {
+		calls++
+		if cid := client.CookieStore.Get("CID"); cid != nil && cid.Value == "fresh" {
+			return nil
+		}
+		return &APIError{StatusCode: 403, Kind: ErrSessionExpired}
+	})
+
+	if err != nil {
+		t.Fatalf("Expected recovery, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls (initial, after rotation, after headless), got %d", calls)
+	}
+	if len(tried) != 2 || tried[0] != "warm-up" || tried[1] != "headless" {
+		t.Errorf("Unexpected strategy order: %v", tried)
+	}
+	if events[len(events)-1] != AuthEventRecovered {
+		t.Errorf("Expected last event to be recovered, got %v", events)
+	}
+}
+
+func TestAuthManagerExhausted(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	manager := NewAuthManager(client, RotationStrategy())
+
+	err := manager.Run(func() error {
+		return &APIError{StatusCode: 403, Kind: ErrSessionExpired}
+	})
+	if !errors.Is(err, ErrAuthExhausted) {
+		t.Errorf("Expected ErrAuthExhausted, got %v", err)
+	}
+}
+
+func TestAuthManagerPassesThroughOtherErrors(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	manager := NewAuthManager(client, NewAuthStrategy("never", func(m *AuthManager) error {
+		t.Error("Strategy should not run for non-session errors")
+		return nil
+	}))
+
+	want := errors.New("HTTP 500: boom")
+	if err := manager.Run(func() error { return want }); err != want {
+		t.Errorf("Expected original error, got %v", err)
+	}
+}
+
+func TestRotationStrategyKeepsRotatedCookies(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+
+	// Another process persisted an older CID
+	stale := &CookieStore{Cookies: map[string]*Cookie{
+		"CID":  {Value: "stale", LastUpdate: time.Now().Add(-time.Hour)},
+		"auth": {Value: "from-disk", LastUpdate: time.Now().Add(-time.Hour)},
+	}}
+	data, _ := json.Marshal(stale)
+	if err := os.WriteFile(client.CookieStore.FilePath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Enqueue(server.Response{
+		Status: http.StatusForbidden,
+		Header: http.Header{"Set-Cookie": {"CID=rotated; Path=/"}},
+		Body:   "Forbidden",
+	})
+	srv.On(server.GetOrderHash, nil, `{"data":{"order":{"id":"TEST123"}}}`)
+
+	manager := NewAuthManager(client, RotationStrategy())
+	err := manager.Run(func() error {
+		_, err := client.GetOrder("TEST123", true)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected the rotated cookies to recover the session, got %v", err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 || requests[1].Cookies["CID"] != "rotated" {
+		t.Fatalf("Expected the retry to send the rotated CID, got %+v", requests)
+	}
+	if requests[1].Cookies["auth"] != "from-disk" {
+		t.Errorf("Expected cookies missing in memory to come from disk, got %q", requests[1].Cookies["auth"])
+	}
+
+	saved, _ := os.ReadFile(client.CookieStore.FilePath)
+	if !strings.Contains(string(saved), `"rotated"`) {
+		t.Errorf("Expected the rotated cookie to be persisted, got %s", saved)
+	}
+}
+
+func TestWarmUpStrategy(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	srv.Enqueue(server.Response{Status: http.StatusOK, Header: http.Header{"Set-Cookie": {"auth=warm; Path=/"}}})
+
+	if err := WarmUpStrategy().Refresh(NewAuthManager(client)); err != nil {
+		t.Fatalf("Warm-up failed: %v", err)
+	}
+	if cookie := client.CookieStore.Get("auth"); cookie == nil || cookie.Value != "warm" {
+		t.Errorf("Expected the page view to rotate cookies, got %+v", cookie)
+	}
+
+	srv.Enqueue(server.Response{Status: http.StatusForbidden})
+	if err := WarmUpStrategy().Refresh(NewAuthManager(client)); err == nil {
+		t.Error("Expected a rejected page view to fail the strategy")
+	}
+}
@@ -0,0 +1,54 @@
+package walmart
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Expected signatures were produced by the AWS SDK for Go v2 signer
+func TestSignAWSRequest(t *testing.T) {
+	creds := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", SessionToken: "tok"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		method, url, service, contentType, target, body, signature string
+	}{
+		{"POST", "https://secretsmanager.us-east-1.amazonaws.com/", "secretsmanager", "application/x-amz-json-1.1",
+			"secretsmanager.GetSecretValue", `{"SecretId":"x"}`, "c858daa0a676872da6796308b227a186fdebe959a251ea5c393208c8365afcb4"},
+		{"PUT", "https://bucket.s3.us-east-1.amazonaws.com/walmart/cookies%20state.json?a-b=2&a=1&a=0", "s3", "application/json",
+			"", `{"cookies":{}}`, "71731fbb74a00ee30213dee1fda600eae9277959f93a3e4a67802f8070f93c4e"},
+		{"GET", "https://bucket.s3.us-east-1.amazonaws.com/key.json", "s3", "", "", "",
+			"9b158e9638af3ea59d0147f50ace167d45eb30c77cca34e9cb91aa0aea317e6e"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body)))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if tt.target != "" {
+			req.Header.Set("X-Amz-Target", tt.target)
+		}
+		signAWSRequest(req, []byte(tt.body), creds, "us-east-1", tt.service, now)
+
+		auth := req.Header.Get("Authorization")
+		if !strings.HasSuffix(auth, "Signature="+tt.signature) {
+			t.Errorf("%s %s: unexpected signature in %s", tt.method, tt.url, auth)
+		}
+		if req.Header.Get("X-Amz-Security-Token") != "tok" {
+			t.Error("Expected the session token header")
+		}
+	}
+}
+
+func TestParseAWSError(t *testing.T) {
+	err := parseAWSError(400, []byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+	if err.Code != "ConditionalCheckFailedException" || err.Message != "The conditional request failed" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	if !isAWSError(err, "ConditionalCheckFailedException") {
+		t.Error("Expected isAWSError to match the code")
+	}
+}
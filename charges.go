@@ -0,0 +1,162 @@
+package walmart
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ChargeKind categorizes non-merchandise price lines
+type ChargeKind string
+
+const (
+	ChargeFee         ChargeKind = "fee"          // Unrecognized fee
+	ChargeDeliveryFee ChargeKind = "delivery_fee" // Standard delivery or shipping fee
+	ChargeExpressFee  ChargeKind = "express_fee"  // Express or priority delivery upgrade
+	ChargeDonation    ChargeKind = "donation"     // Charitable round-up or donation
+
+	// Regional charges, which some stores ring up as items rather than fees
+	ChargeBottleDeposit ChargeKind = "bottle_deposit" // Container deposit or CRV
+	ChargeBagFee        ChargeKind = "bag_fee"        // Checkout bag fee
+)
+
+// Charge is one non-merchandise line on an order
+type Charge struct {
+	Kind   ChargeKind `json:"kind"`
+	Label  string     `json:"label"`
+	Amount float64    `json:"amount"`
+	State  string     `json:"state,omitempty"` // Store state, for regional charges
+}
+
+// chargeRules map label keywords to kinds, checked in order
+var chargeRules = []struct {
+	kind     ChargeKind
+	keywords []string
+}{
+	{ChargeDonation, []string{"donation", "round up", "round-up", "roundup", "charity"}},
+	{ChargeBottleDeposit, []string{"crv", "bottle deposit", "container deposit", "redemption value"}},
+	{ChargeBagFee, []string{"bag fee", "bag charge", "checkout bag", "paper bag", "carryout bag"}},
+	{ChargeExpressFee, []string{"express", "priority", "expedited"}},
+	{ChargeDeliveryFee, []string{"delivery", "shipping"}},
+}
+
+// classifyCharge picks the kind for a price line label
+func classifyCharge(label string) ChargeKind {
+	lower := strings.ToLower(label)
+	for _, rule := range chargeRules {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(lower, keyword) {
+				return rule.kind
+			}
+		}
+	}
+	return ChargeFee
+}
+
+// regionalCharge reports whether kind is rung up as an item in some states
+func regionalCharge(kind ChargeKind) bool {
+	return kind == ChargeBottleDeposit || kind == ChargeBagFee
+}
+
+// itemChargeLabels match whole item names, optionally prefixed with a
+// state code, so merchandise such as "Paper Bags, 100 ct" or "Crv Brand
+// Water" isn't mistaken for a charge
+var itemChargeLabels = []struct {
+	kind    ChargeKind
+	pattern *regexp.Regexp
+}{
+	{ChargeBottleDeposit, regexp.MustCompile(`^(?:[a-z]{2} )?(?:crv|(?:bottle|container|beverage) deposit|(?:california )?redemption value)(?: fee)?$`)},
+	{ChargeBagFee, regexp.MustCompile(`^(?:[a-z]{2} )?(?:checkout |paper |plastic |carryout |reusable )?bag (?:fee|charge)$`)},
+}
+
+// ChargeKind returns the charge kind for deposit and bag fee lines that a
+// store rang up as items, or "" for merchandise
+func (i OrderItem) ChargeKind() ChargeKind {
+	name := strings.Join(strings.Fields(strings.ToLower(i.Name())), " ")
+	for _, label := range itemChargeLabels {
+		if label.pattern.MatchString(name) {
+			return label.kind
+		}
+	}
+	return ""
+}
+
+// StoreState infers the order's state from the first store address
+func (o *Order) StoreState() string {
+	if o == nil {
+		return ""
+	}
+	for _, group := range o.Groups {
+		if group.Store != nil && group.Store.Address.State != "" {
+			return group.Store.Address.State
+		}
+	}
+	return ""
+}
+
+// Charges returns the order's non-merchandise lines with their kinds,
+// including deposit and bag fee lines rung up as items
+func (o *Order) Charges() []Charge {
+	if o == nil {
+		return nil
+	}
+
+	var charges []Charge
+	state := o.StoreState()
+	add := func(kind ChargeKind, label string, amount float64) {
+		charge := Charge{Kind: kind, Label: label, Amount: amount}
+		if regionalCharge(kind) {
+			charge.State = state
+		}
+		charges = append(charges, charge)
+	}
+
+	if o.PriceDetails != nil {
+		for _, fee := range o.PriceDetails.Fees {
+			add(classifyCharge(fee.Label), fee.Label, fee.Value)
+		}
+	}
+	for _, item := range o.GetItems() {
+		if kind := item.ChargeKind(); kind != "" {
+			amount, _ := item.LinePrice()
+			add(kind, item.Name(), amount)
+		}
+	}
+	return charges
+}
+
+// ChargeTotal sums the order's charges of the given kind
+func (o *Order) ChargeTotal(kind ChargeKind) float64 {
+	total := 0.0
+	for _, charge := range o.Charges() {
+		if charge.Kind == kind {
+			total += charge.Amount
+		}
+	}
+	return total
+}
+
+// Delivery tiers
+const (
+	DeliveryTierStandard = "standard"
+	DeliveryTierExpress  = "express"
+)
+
+// DeliveryTier reports whether a delivery fee was paid and at which tier,
+// or "" when the order has no delivery fee line (e.g. free delivery)
+func (o *Order) DeliveryTier() string {
+	tier := ""
+	for _, charge := range o.Charges() {
+		switch charge.Kind {
+		case ChargeExpressFee:
+			return DeliveryTierExpress
+		case ChargeDeliveryFee:
+			tier = DeliveryTierStandard
+		}
+	}
+	return tier
+}
+
+// DonationTotal is the amount given to charity on this order, e.g. for tax records
+func (o *Order) DonationTotal() float64 {
+	return o.ChargeTotal(ChargeDonation)
+}
@@ -0,0 +1,122 @@
+package walmart
+
+import "testing"
+
+func TestOrderCharges(t *testing.T) {
+	order := &Order{
+		PriceDetails: &OrderPriceDetails{
+			Fees: []PriceLineItem{
+				{Label: "Delivery fee", Value: 9.95},
+				{Label: "Round Up for Charity", Value: 0.42},
+				{Label: "Spark Good donation", Value: 1.00},
+				{Label: "Regulatory fee", Value: 0.25},
+			},
+		},
+	}
+
+	charges := order.Charges()
+	expected := []ChargeKind{ChargeDeliveryFee, ChargeDonation, ChargeDonation, ChargeFee}
+	if len(charges) != len(expected) {
+		t.Fatalf("Expected %d charges, got %d", len(expected), len(charges))
+	}
+	for i, kind := range expected {
+		if charges[i].Kind != kind {
+			t.Errorf("%s: expected %s, got %s", charges[i].Label, kind, charges[i].Kind)
+		}
+	}
+
+	if total := order.DonationTotal(); total != 1.42 {
+		t.Errorf("Expected donation total 1.42, got %.2f", total)
+	}
+
+	var missing *Order
+	if missing.Charges() != nil || missing.DonationTotal() != 0 {
+		t.Error("Nil order should have no charges")
+	}
+}
+
+func TestRegionalCharges(t *testing.T) {
+	store := &Store{}
+	store.Address.State = "CA"
+
+	order := &Order{
+		Groups: []OrderGroup{{
+			Store: store,
+			Items: []OrderItem{
+				{ProductInfo: &ProductInfo{Name: "Great Value Sparkling Water 12pk"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 4.98}}},
+				{ProductInfo: &ProductInfo{Name: "CA CRV"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 0.60}}},
+				{ProductInfo: &ProductInfo{Name: "Checkout Bag Fee"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 0.10}}},
+			},
+		}},
+	}
+
+	if state := order.StoreState(); state != "CA" {
+		t.Errorf("Expected CA, got %q", state)
+	}
+	if kind := order.Groups[0].Items[0].ChargeKind(); kind != "" {
+		t.Errorf("Merchandise should have no charge kind, got %s", kind)
+	}
+
+	charges := order.Charges()
+	if len(charges) != 2 {
+		t.Fatalf("Expected 2 regional charges, got %d", len(charges))
+	}
+	if charges[0].Kind != ChargeBottleDeposit || charges[0].State != "CA" || charges[0].Amount != 0.60 {
+		t.Errorf("Unexpected deposit charge: %+v", charges[0])
+	}
+	if total := order.ChargeTotal(ChargeBagFee); total != 0.10 {
+		t.Errorf("Expected bag fee total 0.10, got %.2f", total)
+	}
+}
+
+func TestItemChargeKindIgnoresMerchandise(t *testing.T) {
+	tests := []struct {
+		name string
+		want ChargeKind
+	}{
+		{"CA CRV", ChargeBottleDeposit},
+		{"Bottle Deposit", ChargeBottleDeposit},
+		{"Checkout Bag Fee", ChargeBagFee},
+		{"Paper Bag Charge", ChargeBagFee},
+		{"Paper Bags, 100 ct", ""},
+		{"Brown Paper Bag Lunch Sacks", ""},
+		{"Crvena Zvezda Ajvar", ""},
+		{"Reusable Shopping Bag", ""},
+		{"Bag Fee Sign Holder, Acrylic", ""},
+	}
+	for _, tt := range tests {
+		item := OrderItem{ProductInfo: &ProductInfo{Name: tt.name}}
+		if kind := item.ChargeKind(); kind != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.name, tt.want, kind)
+		}
+	}
+}
+
+func TestExpressDeliveryFees(t *testing.T) {
+	order := &Order{
+		PriceDetails: &OrderPriceDetails{
+			Fees: []PriceLineItem{
+				{Label: "Delivery fee", Value: 7.95},
+				{Label: "Express delivery fee", Value: 10.00},
+			},
+		},
+	}
+
+	if total := order.ChargeTotal(ChargeExpressFee); total != 10 {
+		t.Errorf("Expected express fee 10.00, got %.2f", total)
+	}
+	if total := order.ChargeTotal(ChargeDeliveryFee); total != 7.95 {
+		t.Errorf("Express fee should not count as standard delivery, got %.2f", total)
+	}
+	if tier := order.DeliveryTier(); tier != DeliveryTierExpress {
+		t.Errorf("Expected express tier, got %q", tier)
+	}
+
+	order.PriceDetails.Fees = order.PriceDetails.Fees[:1]
+	if tier := order.DeliveryTier(); tier != DeliveryTierStandard {
+		t.Errorf("Expected standard tier, got %q", tier)
+	}
+	if tier := (&Order{}).DeliveryTier(); tier != "" {
+		t.Errorf("Expected no tier without a delivery fee, got %q", tier)
+	}
+}
@@ -0,0 +1,43 @@
+package walmart
+
+// OrderClassifier labels a history entry, for example "spark" for Spark
+// driver activity mixed into a personal account. Return "" to leave the
+// order unclassified. The first classifier with a label wins.
+type OrderClassifier func(order *OrderSummary) string
+
+// classifyOrders applies the configured classifiers in place
+func (c *WalmartClient) classifyOrders(orders []OrderSummary) {
+	if len(c.classifiers) == 0 {
+		return
+	}
+
+	for i := range orders {
+		for _, classify := range c.classifiers {
+			if label := classify(&orders[i]); label != "" {
+				orders[i].Classification = label
+				break
+			}
+		}
+	}
+}
+
+// ExcludeClassified drops orders carrying any of the given labels, or any
+// label at all when none are given, so only unflagged personal orders remain
+func ExcludeClassified(orders []OrderSummary, labels ...string) []OrderSummary {
+	excluded := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		excluded[label] = true
+	}
+
+	var kept []OrderSummary
+	for _, order := range orders {
+		if order.Classification == "" {
+			kept = append(kept, order)
+			continue
+		}
+		if len(labels) > 0 && !excluded[order.Classification] {
+			kept = append(kept, order)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,42 @@
+package walmart
+
+import "testing"
+
+func TestClassifyOrders(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{
+		CookieDir: t.TempDir(),
+		Classifiers: []OrderClassifier{
+			func(o *OrderSummary) string {
+				if o.Store != nil && o.Store.ID == "spark-hub" {
+					return "spark"
+				}
+				return ""
+			},
+			func(o *OrderSummary) string {
+				if o.Type == "GLASS" {
+					return "online"
+				}
+				return ""
+			},
+		},
+	})
+
+	orders := []OrderSummary{
+		{OrderID: "1", Type: "IN_STORE"},
+		{OrderID: "2", Type: "GLASS", Store: &StoreInfo{ID: "spark-hub"}},
+		{OrderID: "3", Type: "GLASS"},
+	}
+	client.classifyOrders(orders)
+
+	if orders[0].Classification != "" || orders[1].Classification != "spark" || orders[2].Classification != "online" {
+		t.Errorf("Unexpected classifications: %q %q %q",
+			orders[0].Classification, orders[1].Classification, orders[2].Classification)
+	}
+
+	if kept := ExcludeClassified(orders, "spark"); len(kept) != 2 {
+		t.Errorf("Expected 2 orders after excluding spark, got %d", len(kept))
+	}
+	if kept := ExcludeClassified(orders); len(kept) != 1 || kept[0].OrderID != "1" {
+		t.Errorf("Expected only the unclassified order, got %v", kept)
+	}
+}
@@ -1,13 +1,17 @@
 package walmart
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,42 +21,135 @@ import (
 type WalmartClient struct {
 	httpClient  *http.Client
 	CookieStore *CookieStore
-	rateLimiter *time.Ticker
+	limiters    limiters
+	paceMu      sync.Mutex
 	lastRequest time.Time
 	mu          sync.RWMutex
+
+	fingerprintPolicy FingerprintPolicy
+	fingerprintWarn   sync.Once
+	classifiers       []OrderClassifier
+	nonInteractive    bool
+	pacing            *PacingProfile
+	retry             *RetryPolicy
+	baseURL           string
+	hooks             []Hooks
+	logger            *slog.Logger
+	metrics           Metrics
+	headerMu          sync.RWMutex
+	headerProfile     HeaderProfile
+	headerOverrides   map[string]string
+	hashMu            sync.RWMutex
+	queryHashes       map[string]string
+	stats             operationTracker
 }
 
 // CookieStore manages cookies with persistence and auto-updates
 type CookieStore struct {
-	Cookies    map[string]*Cookie `json:"cookies"`
-	LastUpdate time.Time          `json:"last_update"`
-	FilePath   string             `json:"-"`
-	mu         sync.RWMutex
+	Cookies     map[string]*Cookie  `json:"cookies"`
+	LastUpdate  time.Time           `json:"last_update"`
+	Fingerprint *SessionFingerprint `json:"fingerprint,omitempty"` // Header profile the cookies were captured with
+	Deleted     map[string]*Cookie  `json:"deleted,omitempty"`     // Cookies the server expired, for RollbackCookie
+	FilePath    string              `json:"-"`
+	mu          sync.RWMutex
 }
 
 // Cookie represents a cookie with metadata
 type Cookie struct {
 	Value      string    `json:"value"`
 	LastUpdate time.Time `json:"last_update"`
-	Source     string    `json:"source"` // "curl", "response", "manual"
+	Source     string    `json:"source"` // "curl", "relay", "extension", "env", "response", "manual"
 	Essential  bool      `json:"essential"`
+
+	Domain  string     `json:"domain,omitempty"`  // Domain attribute from Set-Cookie
+	Expires *time.Time `json:"expires,omitempty"` // Expiry from Set-Cookie; nil for session cookies
+
+	// History keeps earlier values, oldest first, so a bad overwrite can be undone
+	History []CookieVersion `json:"history,omitempty"`
 }
 
+// DefaultBaseURL is where requests go unless ClientConfig.BaseURL is set
+const DefaultBaseURL = "https://www.walmart.com"
+
 // ClientConfig for initializing the client
 type ClientConfig struct {
 	CookieFile string        `json:"cookie_file"`
 	RateLimit  time.Duration `json:"rate_limit"`
 	AutoSave   bool          `json:"auto_save"`
 	CookieDir  string        `json:"cookie_dir"`
+
+	// FingerprintPolicy decides whether header profile drift warns or fails
+	FingerprintPolicy FingerprintPolicy `json:"fingerprint_policy"`
+
+	// Classifiers label history entries, e.g. to flag Spark driver activity
+	Classifiers []OrderClassifier `json:"-"`
+
+	// NonInteractive turns prompts into InteractionRequiredError, for daemons
+	NonInteractive bool `json:"non_interactive"`
+
+	// Pacing replaces the fixed RateLimit ticker with randomized delays
+	Pacing *PacingProfile `json:"pacing,omitempty"`
+
+	// Stateless reads cookies from WALMART_COOKIES / WALMART_COOKIES_FILE and
+	// writes nothing to disk unless CookieDir or CookieFile is set explicitly
+	Stateless bool `json:"stateless"`
+
+	// Retry retries transient failures; nil fails on the first error
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// HTTPClient replaces the default client, e.g. to add a proxy. Redirects
+	// are still not followed unless it sets its own CheckRedirect.
+	HTTPClient *http.Client `json:"-"`
+
+	// Transport is used by the default client, e.g. for instrumentation or
+	// record/replay; ignored when HTTPClient is set
+	Transport http.RoundTripper `json:"-"`
+
+	// Logger receives progress and diagnostics; nil discards them
+	Logger *slog.Logger `json:"-"`
+
+	// Metrics receives request counts, latencies, retries, rate-limit waits
+	// and session age; nil disables them
+	Metrics Metrics `json:"-"`
+
+	// Hooks run around every request, in order
+	Hooks []Hooks `json:"-"`
+
+	// RateLimits overrides RateLimit per operation, e.g. OperationGetOrder
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+
+	// Proxy routes requests through an http, https or socks5 proxy URL
+	Proxy string `json:"proxy,omitempty"`
+
+	// ProxyFunc picks a proxy per request, e.g. RotatingProxy; cannot be
+	// combined with Proxy
+	ProxyFunc ProxyFunc `json:"-"`
+
+	// HeaderProfile is the browser identity to claim; defaults to DesktopChrome
+	HeaderProfile *HeaderProfile `json:"header_profile,omitempty"`
+
+	// HeaderOverrides set individual headers after the profile; an empty
+	// value removes the header
+	HeaderOverrides map[string]string `json:"header_overrides,omitempty"`
+
+	// QueryHashes overrides the persisted-query hash per operation, e.g.
+	// OperationGetOrder, when Walmart redeploys before this package updates
+	QueryHashes map[string]string `json:"query_hashes,omitempty"`
+
+	// DialTLSContext replaces the TLS handshake, e.g. with a uTLS dialer that
+	// presents a Chrome client hello to match the user-agent
+	DialTLSContext func(ctx context.Context, network, addr string) (net.Conn, error) `json:"-"`
+
+	// BaseURL replaces https://www.walmart.com, e.g. for test servers or proxies
+	BaseURL string `json:"base_url,omitempty"`
 }
 
 // NewWalmartClient creates a robust client with cookie management
 func NewWalmartClient(config ClientConfig) (*WalmartClient, error) {
 	// Set defaults
-	if config.CookieFile == "" {
+	if config.CookieFile == "" && !(config.Stateless && config.CookieDir == "") {
 		if config.CookieDir == "" {
-			homeDir, _ := os.UserHomeDir()
-			config.CookieDir = filepath.Join(homeDir, ".walmart-api")
+			config.CookieDir = DefaultDataDir()
 		}
 		_ = os.MkdirAll(config.CookieDir, 0755)
 		config.CookieFile = filepath.Join(config.CookieDir, "cookies.json")
@@ -62,6 +159,22 @@ func NewWalmartClient(config ClientConfig) (*WalmartClient, error) {
 		config.RateLimit = 2 * time.Second
 	}
 
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+
+	if config.Metrics == nil {
+		config.Metrics = noopMetrics{}
+	}
+
+	transport, err := defaultTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	if transport != nil {
+		config.Transport = transport
+	}
+
 	// Initialize cookie store
 	store := &CookieStore{
 		Cookies:  make(map[string]*Cookie),
@@ -72,20 +185,53 @@ func NewWalmartClient(config ClientConfig) (*WalmartClient, error) {
 	_ = store.Load() // Ignore error, just means no existing cookies
 
 	client := &WalmartClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			// Don't follow redirects automatically
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		},
-		CookieStore: store,
-		rateLimiter: time.NewTicker(config.RateLimit),
+		httpClient:        newHTTPClient(config),
+		CookieStore:       store,
+		limiters:          limiters{fallback: RateLimit{Interval: config.RateLimit, Burst: 1}, limits: config.RateLimits},
+		fingerprintPolicy: config.FingerprintPolicy,
+		classifiers:       config.Classifiers,
+		nonInteractive:    config.NonInteractive,
+		pacing:            config.Pacing,
+		retry:             config.Retry,
+		baseURL:           strings.TrimSuffix(config.BaseURL, "/"),
+		hooks:             config.Hooks,
+		logger:            newLogger(config.Logger),
+		metrics:           config.Metrics,
+		headerProfile:     DesktopChrome(),
+		headerOverrides:   config.HeaderOverrides,
+	}
+
+	if config.HeaderProfile != nil {
+		client.headerProfile = *config.HeaderProfile
+	}
+	client.SetQueryHashes(config.QueryHashes)
+
+	if config.Stateless {
+		if err := client.loadCookiesFromEnv(); err != nil {
+			return nil, err
+		}
 	}
 
 	return client, nil
 }
 
+// newHTTPClient builds the client requests are sent with
+func newHTTPClient(config ClientConfig) *http.Client {
+	var httpClient http.Client
+	if config.HTTPClient != nil {
+		httpClient = *config.HTTPClient
+	} else {
+		httpClient = http.Client{Timeout: 30 * time.Second, Transport: config.Transport}
+	}
+	if httpClient.CheckRedirect == nil {
+		// Don't follow redirects automatically
+		httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return &httpClient
+}
+
 // InitializeFromCurl loads cookies from a curl command file
 func (c *WalmartClient) InitializeFromCurl(curlFile string) error {
 	data, err := os.ReadFile(curlFile)
@@ -93,8 +239,17 @@ func (c *WalmartClient) InitializeFromCurl(curlFile string) error {
 		return fmt.Errorf("failed to read curl file: %w", err)
 	}
 
-	cookies := extractCookiesFromCurl(string(data))
+	if fingerprint := newFingerprint(extractHeadersFromCurl(string(data)), "curl"); fingerprint != nil {
+		c.CookieStore.mu.Lock()
+		c.CookieStore.Fingerprint = fingerprint
+		c.CookieStore.mu.Unlock()
+	}
 
+	return c.importCookies(extractCookiesFromCurl(string(data)), "curl")
+}
+
+// importCookies stores cookies from an external source and persists the store
+func (c *WalmartClient) importCookies(cookies map[string]string, source string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -105,7 +260,7 @@ func (c *WalmartClient) InitializeFromCurl(curlFile string) error {
 		cookie := &Cookie{
 			Value:      value,
 			LastUpdate: time.Now(),
-			Source:     "curl",
+			Source:     source,
 			Essential:  false,
 		}
 
@@ -130,11 +285,16 @@ func (c *WalmartClient) InitializeFromCurl(curlFile string) error {
 
 // GetOrder fetches an order with automatic cookie updates
 func (c *WalmartClient) GetOrder(orderID string, isInStore bool) (*Order, error) {
-	// Rate limiting - only wait if not first request
-	if !c.lastRequest.IsZero() {
-		<-c.rateLimiter.C
-	}
-	c.lastRequest = time.Now()
+	var order *Order
+	err := c.withRetry(OperationGetOrder, func() (err error) {
+		order, err = c.getOrder(orderID, isInStore)
+		return err
+	})
+	return order, err
+}
+
+func (c *WalmartClient) getOrder(orderID string, isInStore bool) (*Order, error) {
+	c.pace(OperationGetOrder)
 
 	endpoint := c.buildOrderEndpoint(orderID, isInStore)
 
@@ -145,12 +305,15 @@ func (c *WalmartClient) GetOrder(orderID string, isInStore bool) (*Order, error)
 
 	// Set headers
 	c.setHeaders(req)
+	if err := c.checkFingerprint(req); err != nil {
+		return nil, err
+	}
 
 	// Set cookies from store
 	c.setCookies(req)
 
 	// Execute request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(OperationGetOrder, req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -167,13 +330,7 @@ func (c *WalmartClient) GetOrder(orderID string, isInStore bool) (*Order, error)
 
 	// Check status
 	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == 429 {
-			return nil, fmt.Errorf("rate limited - cookies might be stale, try refreshing from browser")
-		}
-		if resp.StatusCode == 403 || resp.StatusCode == 418 {
-			return nil, fmt.Errorf("access denied - cookies expired, please update from browser")
-		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(OperationGetOrder, req, resp, body)
 	}
 
 	// Parse response
@@ -183,7 +340,16 @@ func (c *WalmartClient) GetOrder(orderID string, isInStore bool) (*Order, error)
 	}
 
 	if orderResp.Data.Order == nil {
-		return nil, fmt.Errorf("no order data in response")
+		kind := ErrOrderNotFound
+		if persistedQueryNotFound(body) {
+			kind = ErrStaleQueryHash
+		}
+		return nil, &APIError{
+			Operation:     OperationGetOrder,
+			StatusCode:    resp.StatusCode,
+			CorrelationID: correlationID(req, resp),
+			Kind:          kind,
+		}
 	}
 
 	order := orderResp.Data.Order
@@ -234,7 +400,7 @@ func (c *WalmartClient) GetDeliveryOrderWithTip(orderID string) (*Order, error)
 
 // updateCookiesFromResponse updates cookie store with Set-Cookie headers
 func (c *WalmartClient) updateCookiesFromResponse(resp *http.Response) {
-	setCookies := resp.Header["Set-Cookie"]
+	setCookies := resp.Cookies()
 	if len(setCookies) == 0 {
 		return
 	}
@@ -242,95 +408,157 @@ func (c *WalmartClient) updateCookiesFromResponse(resp *http.Response) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	updatedCount := 0
-	for _, cookieHeader := range setCookies {
-		parts := strings.Split(cookieHeader, ";")
-		if len(parts) > 0 {
-			nameValue := strings.SplitN(parts[0], "=", 2)
-			if len(nameValue) == 2 {
-				name := strings.TrimSpace(nameValue[0])
-				value := strings.TrimSpace(nameValue[1])
-
-				// Check if this is an update
-				existing := c.CookieStore.Get(name)
-				if existing != nil && existing.Value != value {
-					updatedCount++
-				}
-
-				c.CookieStore.Set(name, &Cookie{
-					Value:      value,
-					LastUpdate: time.Now(),
-					Source:     "response",
-					Essential:  existing != nil && existing.Essential,
-				})
-			}
+	now := time.Now()
+	for _, sc := range setCookies {
+		// Max-Age=0 or negative is how the server deletes a cookie
+		if sc.MaxAge < 0 {
+			c.CookieStore.Delete(sc.Name)
+			continue
 		}
+		existing := c.CookieStore.Get(sc.Name)
+		c.CookieStore.Set(sc.Name, &Cookie{
+			Value:      sc.Value,
+			LastUpdate: now,
+			Source:     "response",
+			Essential:  existing != nil && existing.Essential,
+			Domain:     sc.Domain,
+			Expires:    cookieExpiry(sc, now),
+		})
 	}
+}
 
-	// Silently update cookies
+// cookieExpiry resolves Max-Age (which takes precedence) or Expires
+func cookieExpiry(sc *http.Cookie, now time.Time) *time.Time {
+	var expires time.Time
+	switch {
+	case sc.MaxAge > 0:
+		expires = now.Add(time.Duration(sc.MaxAge) * time.Second)
+	case !sc.Expires.IsZero():
+		expires = sc.Expires
+	default:
+		return nil
+	}
+	return &expires
 }
 
-// Status shows the current state of cookies
-func (c *WalmartClient) Status() {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// staleCookieAge is when Status considers a cookie potentially stale
+const staleCookieAge = time.Hour
 
-	fmt.Println("\n=== Cookie Store Status ===")
-	fmt.Printf("Total cookies: %d\n", len(c.CookieStore.Cookies))
-	fmt.Printf("Cookie file: %s\n", c.CookieStore.FilePath)
-	fmt.Printf("Last update: %s\n", c.CookieStore.LastUpdate.Format(time.RFC3339))
+// CookieStatus summarizes the cookie store, see WalmartClient.Status
+type CookieStatus struct {
+	Total      int            `json:"total"`
+	File       string         `json:"file"`
+	LastUpdate time.Time      `json:"last_update"`
+	Essential  int            `json:"essential"` // Cookies flagged essential
+	Stale      int            `json:"stale"`     // Cookies not updated for over an hour
+	BySource   map[string]int `json:"by_source"`
 
-	// Count by source
-	sources := make(map[string]int)
-	essential := 0
-	stale := 0
+	// EssentialCookies lists every essential cookie name, present or not
+	EssentialCookies []EssentialCookieStatus `json:"essential_cookies"`
+}
 
-	for _, cookie := range c.CookieStore.Cookies {
-		sources[cookie.Source]++
-		if cookie.Essential {
-			essential++
-		}
-		// Consider cookies older than 1 hour as potentially stale
-		if time.Since(cookie.LastUpdate) > time.Hour {
-			stale++
+// EssentialCookieStatus is the state of one essential cookie
+type EssentialCookieStatus struct {
+	Name    string        `json:"name"`
+	Present bool          `json:"present"`
+	Age     time.Duration `json:"age"` // Since its last update; zero when missing
+}
+
+// String renders the status as the human-readable report the CLI prints
+func (s CookieStatus) String() string {
+	var b strings.Builder
+	b.WriteString("=== Cookie Store Status ===\n")
+	fmt.Fprintf(&b, "Total cookies: %d\n", s.Total)
+	fmt.Fprintf(&b, "Cookie file: %s\n", s.File)
+	fmt.Fprintf(&b, "Last update: %s\n", s.LastUpdate.Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "\nEssential cookies: %d\n", s.Essential)
+	fmt.Fprintf(&b, "Potentially stale: %d (>1 hour old)\n", s.Stale)
+
+	b.WriteString("\nCookies by source:\n")
+	sources := make([]string, 0, len(s.BySource))
+	for source := range s.BySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		fmt.Fprintf(&b, "  %s: %d\n", source, s.BySource[source])
+	}
+
+	b.WriteString("\nEssential cookies:\n")
+	for _, cookie := range s.EssentialCookies {
+		switch {
+		case !cookie.Present:
+			fmt.Fprintf(&b, "  ❌ %s: MISSING\n", cookie.Name)
+		case cookie.Age > staleCookieAge:
+			fmt.Fprintf(&b, "  ⚠️ %s: %s ago\n", cookie.Name, cookie.Age.Round(time.Second))
+		default:
+			fmt.Fprintf(&b, "  ✅ %s: %s ago\n", cookie.Name, cookie.Age.Round(time.Second))
 		}
 	}
+	return b.String()
+}
 
-	fmt.Printf("\nEssential cookies: %d\n", essential)
-	fmt.Printf("Potentially stale: %d (>1 hour old)\n", stale)
+// Status summarizes the current state of cookies. Print it with
+// fmt.Println(client.Status()) or log its fields.
+func (c *WalmartClient) Status() CookieStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	fmt.Println("\nCookies by source:")
-	for source, count := range sources {
-		fmt.Printf("  %s: %d\n", source, count)
+	cs := c.CookieStore
+	cs.mu.RLock()
+	status := CookieStatus{
+		Total:      len(cs.Cookies),
+		File:       cs.FilePath,
+		LastUpdate: cs.LastUpdate,
+		BySource:   make(map[string]int),
+	}
+	for _, cookie := range cs.Cookies {
+		status.BySource[cookie.Source]++
+		if cookie.Essential {
+			status.Essential++
+		}
+		if time.Since(cookie.LastUpdate) > staleCookieAge {
+			status.Stale++
+		}
 	}
-
-	// Show essential cookies status
-	fmt.Println("\nEssential cookies:")
-	essentials := []string{"CID", "SPID", "auth", "customer"}
-	for _, name := range essentials {
-		if cookie := c.CookieStore.Get(name); cookie != nil {
-			age := time.Since(cookie.LastUpdate)
-			status := "✅"
-			if age > time.Hour {
-				status = "⚠️"
-			}
-			fmt.Printf("  %s %s: %s ago\n", status, name, age.Round(time.Second))
-		} else {
-			fmt.Printf("  ❌ %s: MISSING\n", name)
+	for _, name := range essentialCookies {
+		entry := EssentialCookieStatus{Name: name}
+		if cookie := cs.Cookies[name]; cookie != nil {
+			entry.Present = true
+			entry.Age = time.Since(cookie.LastUpdate)
 		}
+		status.EssentialCookies = append(status.EssentialCookies, entry)
 	}
+	cs.mu.RUnlock()
+	return status
+}
+
+// BrowserRefreshSteps explains how to capture fresh cookies from a browser
+var BrowserRefreshSteps = []string{
+	"Open Chrome/Firefox and log into walmart.com",
+	"Go to your orders page",
+	"Open DevTools (F12) → Network tab",
+	"Refresh the page",
+	"Find any 'getOrder' request",
+	"Right-click → Copy → Copy as cURL",
+	"Paste into a file and provide the path below",
 }
 
 // RefreshFromBrowser prompts user to get fresh cookies
 func (c *WalmartClient) RefreshFromBrowser() error {
+	if c.nonInteractive {
+		return &InteractionRequiredError{
+			Action:      "refresh_cookies",
+			Remediation: RemediationImportCurl,
+			Steps:       BrowserRefreshSteps[:len(BrowserRefreshSteps)-1],
+		}
+	}
+
 	fmt.Println("\n=== Refresh Cookies from Browser ===")
-	fmt.Println("1. Open Chrome/Firefox and log into walmart.com")
-	fmt.Println("2. Go to your orders page")
-	fmt.Println("3. Open DevTools (F12) → Network tab")
-	fmt.Println("4. Refresh the page")
-	fmt.Println("5. Find any 'getOrder' request")
-	fmt.Println("6. Right-click → Copy → Copy as cURL")
-	fmt.Println("7. Paste into a file and provide the path below")
+	for i, step := range BrowserRefreshSteps {
+		fmt.Printf("%d. %s\n", i+1, step)
+	}
 	fmt.Print("\nPath to curl file (or 'skip' to cancel): ")
 
 	var path string
@@ -354,6 +582,13 @@ func (cs *CookieStore) Get(name string) *Cookie {
 func (cs *CookieStore) Set(name string, cookie *Cookie) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
+	prev := cs.Cookies[name]
+	if prev == nil {
+		// A deleted cookie's value goes into the history of its replacement
+		prev = cs.Deleted[name]
+		delete(cs.Deleted, name)
+	}
+	cookie.History = carryHistory(prev, cookie)
 	cs.Cookies[name] = cookie
 	cs.LastUpdate = time.Now()
 }
@@ -362,6 +597,11 @@ func (cs *CookieStore) Load() error {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
+	// Stateless stores have no file
+	if cs.FilePath == "" {
+		return nil
+	}
+
 	data, err := os.ReadFile(cs.FilePath)
 	if err != nil {
 		return err
@@ -370,9 +610,50 @@ func (cs *CookieStore) Load() error {
 	return json.Unmarshal(data, cs)
 }
 
+// mergeSaved pulls in cookies persisted by other processes, keeping
+// whichever copy of each cookie was updated last
+func (cs *CookieStore) mergeSaved() error {
+	if cs.FilePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(cs.FilePath)
+	if err != nil {
+		return err
+	}
+	var saved CookieStore
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for name, cookie := range saved.Cookies {
+		if deleted, ok := cs.Deleted[name]; ok {
+			// Only a copy set after the one deleted here brings it back
+			if !cookie.LastUpdate.After(deleted.LastUpdate) {
+				continue
+			}
+			delete(cs.Deleted, name)
+		}
+		if current, ok := cs.Cookies[name]; !ok || cookie.LastUpdate.After(current.LastUpdate) {
+			cs.Cookies[name] = cookie
+		}
+	}
+	if saved.LastUpdate.After(cs.LastUpdate) {
+		cs.LastUpdate = saved.LastUpdate
+	}
+	return nil
+}
+
 func (cs *CookieStore) Save() error {
-	cs.mu.RLock()
-	defer cs.mu.RUnlock()
+	// A full lock so concurrent saves don't interleave their writes
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.FilePath == "" {
+		return nil
+	}
 
 	data, err := json.MarshalIndent(cs, "", "  ")
 	if err != nil {
@@ -400,42 +681,19 @@ func (c *WalmartClient) buildOrderEndpoint(orderID string, isInStore bool) strin
 	params := url.Values{}
 	params.Set("variables", string(variablesJSON))
 
-	return fmt.Sprintf("https://www.walmart.com/orchestra/orders/graphql/getOrder/d0622497daef19150438d07c506739d451cad6749cf45c3b4db95f2f5a0a65c4?%s",
-		params.Encode())
+	return fmt.Sprintf("%s/orchestra/orders/graphql/getOrder/%s?%s",
+		c.baseURL, c.queryHash(OperationGetOrder), params.Encode())
 }
 
 func (c *WalmartClient) setHeaders(req *http.Request) {
-	headers := map[string]string{
-		"accept":                  "application/json",
-		"accept-language":         "en-US",
-		"content-type":            "application/json",
-		"user-agent":              "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
-		"x-apollo-operation-name": "getOrder",
-		"x-o-gql-query":           "query getOrder",
-		"x-o-platform":            "rweb",
-		"x-o-bu":                  "WALMART-US",
-		"x-o-mart":                "B2C",
-		"x-o-segment":             "oaoh",
-		"x-o-correlation-id":      fmt.Sprintf("walmart-go-%d", time.Now().Unix()),
-		"wm_qos.correlation_id":   fmt.Sprintf("walmart-go-%d", time.Now().Unix()),
-		"wm_mp":                   "true",
-		"sec-fetch-site":          "same-origin",
-		"sec-fetch-mode":          "cors",
-		"sec-fetch-dest":          "empty",
-		"dnt":                     "1",
-		"x-o-platform-version":    "usweb-1.221.0",
-		"x-enable-server-timing":  "1",
-		"x-latency-trace":         "1",
-	}
-
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
+	c.setOperationHeaders(req, OperationGetOrder)
 }
 
 func (c *WalmartClient) setCookies(req *http.Request) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	c.CookieStore.mu.RLock()
+	defer c.CookieStore.mu.RUnlock()
 
 	var cookiePairs []string
 	for name, cookie := range c.CookieStore.Cookies {
@@ -470,3 +728,14 @@ func extractCookiesFromCurl(curlCmd string) map[string]string {
 	}
 	return cookies
 }
+
+func parseCookieHeader(header string) map[string]string {
+	cookies := make(map[string]string)
+	for _, pair := range strings.Split(header, ";") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) == 2 && parts[0] != "" {
+			cookies[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return cookies
+}
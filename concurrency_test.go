@@ -0,0 +1,77 @@
+package walmart
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestConcurrentRequests(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	client.limiters = limiters{fallback: RateLimit{Interval: time.Millisecond, Burst: 4}}
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("ORDER%d", i)
+		srv.On(server.GetOrderHash, map[string]interface{}{"orderId": id}, fmt.Sprintf(`{"data":{"order":{"id":%q}}}`, id))
+	}
+	srv.On(server.PurchaseHistoryHash, nil, `{"data":{"orderHistoryV2":{"orderGroups":[]}}}`)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 12)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			order, err := client.GetOrder(id, true)
+			if err == nil && order.ID != id {
+				err = fmt.Errorf("got order %s for %s", order.ID, id)
+			}
+			errs <- err
+		}(fmt.Sprintf("ORDER%d", i))
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetRecentOrders(5)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if stats := client.Stats()[OperationGetOrder]; stats.Requests != 8 {
+		t.Errorf("Expected 8 recorded getOrder requests, got %+v", stats)
+	}
+}
+
+func TestConcurrentPacedRequests(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	client.pacing = &PacingProfile{MinDelay: 5 * time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	srv.On(server.GetOrderHash, nil, `{"data":{"order":{"id":"ORDER"}}}`)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetOrder("ORDER", true); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first request goes straight out, the other four are spaced apart
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected concurrent requests to be paced, took %s", elapsed)
+	}
+}
@@ -0,0 +1,94 @@
+package walmart
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxCookieHistory bounds how many earlier values are kept per cookie
+const maxCookieHistory = 5
+
+// CookieVersion is a previous value of a cookie and where it came from
+type CookieVersion struct {
+	Value      string     `json:"value"`
+	LastUpdate time.Time  `json:"last_update"`
+	Source     string     `json:"source"`
+	Domain     string     `json:"domain,omitempty"`
+	Expires    *time.Time `json:"expires,omitempty"`
+}
+
+// carryHistory builds the history for next, recording prev when the value changed
+func carryHistory(prev, next *Cookie) []CookieVersion {
+	if prev == nil {
+		return next.History
+	}
+
+	history := prev.History
+	if prev.Value != next.Value {
+		history = append(history, CookieVersion{
+			Value:      prev.Value,
+			LastUpdate: prev.LastUpdate,
+			Source:     prev.Source,
+			Domain:     prev.Domain,
+			Expires:    prev.Expires,
+		})
+	}
+	if len(history) > maxCookieHistory {
+		history = history[len(history)-maxCookieHistory:]
+	}
+	return append([]CookieVersion(nil), history...)
+}
+
+// Delete removes a cookie the server expired. It is kept under Deleted, so
+// the removal shows up in history and RollbackCookie can undo it.
+func (cs *CookieStore) Delete(name string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cookie, ok := cs.Cookies[name]
+	if !ok {
+		return
+	}
+	if cs.Deleted == nil {
+		cs.Deleted = make(map[string]*Cookie)
+	}
+	cs.Deleted[name] = cookie
+	delete(cs.Cookies, name)
+	cs.LastUpdate = time.Now()
+}
+
+// RollbackCookie restores the previous value of a cookie, e.g. after a
+// challenge page's Set-Cookie clobbered or deleted a working auth cookie
+func (c *WalmartClient) RollbackCookie(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs := c.CookieStore
+	cs.mu.Lock()
+	if deleted, ok := cs.Deleted[name]; ok && cs.Cookies[name] == nil {
+		cs.Cookies[name] = deleted
+		delete(cs.Deleted, name)
+		cs.LastUpdate = time.Now()
+		cs.mu.Unlock()
+		return cs.Save()
+	}
+	cookie, ok := cs.Cookies[name]
+	if !ok || len(cookie.History) == 0 {
+		cs.mu.Unlock()
+		return fmt.Errorf("no previous value for cookie %s", name)
+	}
+
+	last := cookie.History[len(cookie.History)-1]
+	cs.Cookies[name] = &Cookie{
+		Value:      last.Value,
+		LastUpdate: last.LastUpdate,
+		Source:     last.Source,
+		Essential:  cookie.Essential,
+		Domain:     last.Domain,
+		Expires:    last.Expires,
+		History:    cookie.History[:len(cookie.History)-1],
+	}
+	cs.LastUpdate = time.Now()
+	cs.mu.Unlock()
+
+	return cs.Save()
+}
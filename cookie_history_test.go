@@ -0,0 +1,67 @@
+package walmart
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCookieHistoryAndRollback(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	store := client.CookieStore
+
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	store.Set("auth", &Cookie{Value: "good", Source: "curl", Essential: true, LastUpdate: time.Now()})
+	store.Set("auth", &Cookie{Value: "good", Source: "response", Essential: true, Domain: ".walmart.com", Expires: &expires})
+	if got := len(store.Get("auth").History); got != 0 {
+		t.Errorf("Unchanged value should not add history, got %d entries", got)
+	}
+
+	store.Set("auth", &Cookie{Value: "challenge", Source: "response", Essential: true})
+	if err := client.RollbackCookie("auth"); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	cookie := store.Get("auth")
+	if cookie.Value != "good" || cookie.Source != "response" || !cookie.Essential {
+		t.Errorf("Unexpected cookie after rollback: %+v", cookie)
+	}
+	if cookie.Domain != ".walmart.com" || cookie.Expires == nil || !cookie.Expires.Equal(expires) {
+		t.Errorf("Expected Domain and Expires to be restored, got %+v", cookie)
+	}
+	if err := client.RollbackCookie("auth"); err == nil {
+		t.Error("Expected error when no history is left")
+	}
+}
+
+func TestRollbackDeletedCookie(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	store := client.CookieStore
+
+	store.Set("auth", &Cookie{Value: "good", Source: "curl", Essential: true})
+	store.Delete("auth")
+	if store.Get("auth") != nil {
+		t.Fatal("Expected the cookie to be deleted")
+	}
+	if err := client.RollbackCookie("auth"); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if cookie := store.Get("auth"); cookie == nil || cookie.Value != "good" || !cookie.Essential {
+		t.Errorf("Expected the deleted cookie back, got %+v", cookie)
+	}
+}
+
+func TestCookieHistoryIsBounded(t *testing.T) {
+	store := &CookieStore{Cookies: make(map[string]*Cookie)}
+	for i := 0; i < maxCookieHistory+3; i++ {
+		store.Set("CID", &Cookie{Value: fmt.Sprintf("v%d", i)})
+	}
+
+	history := store.Get("CID").History
+	if len(history) != maxCookieHistory {
+		t.Fatalf("Expected %d history entries, got %d", maxCookieHistory, len(history))
+	}
+	if history[0].Value != "v2" {
+		t.Errorf("Expected oldest entries to be dropped, got %s", history[0].Value)
+	}
+}
@@ -0,0 +1,135 @@
+package walmart
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookiePushSignatureHeader carries the hex HMAC-SHA256 of the request body
+const CookiePushSignatureHeader = "X-Walmart-Signature"
+
+// CookiePush is the payload a companion browser extension posts whenever
+// fresh walmart.com cookies are available
+type CookiePush struct {
+	Timestamp int64             `json:"timestamp"`       // Unix seconds when the push was created
+	Nonce     string            `json:"nonce,omitempty"` // Random per push, so two pushes in one second differ
+	Cookies   map[string]string `json:"cookies"`         // Cookie name to value
+}
+
+// maxSeenPushes bounds how many accepted pushes are remembered for replay checks
+const maxSeenPushes = 1024
+
+// CookiePushReceiver is an http.Handler that accepts signed cookie pushes
+type CookiePushReceiver struct {
+	// MaxSkew is how far a push timestamp may drift from local time
+	MaxSkew time.Duration
+
+	client *WalmartClient
+	secret []byte
+	seen   map[string]time.Time // Signatures of recently accepted pushes
+	pushed chan struct{}
+	mu     sync.Mutex
+}
+
+// ReceiveCookiePush returns a handler for browser extension pushes signed
+// with the shared secret. Mount it on a localhost-only listener.
+func (c *WalmartClient) ReceiveCookiePush(secret []byte) *CookiePushReceiver {
+	return &CookiePushReceiver{
+		MaxSkew: 5 * time.Minute,
+		client:  c,
+		secret:  secret,
+		seen:    make(map[string]time.Time),
+		pushed:  make(chan struct{}, 1),
+	}
+}
+
+// Pushed receives a value after each successfully imported push
+func (r *CookiePushReceiver) Pushed() <-chan struct{} {
+	return r.pushed
+}
+
+// SignCookiePush computes the signature header value for a push body
+func SignCookiePush(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP verifies and imports a cookie push
+func (r *CookiePushReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRelayBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	expected := SignCookiePush(r.secret, body)
+	got := strings.TrimSpace(req.Header.Get(CookiePushSignatureHeader))
+	if len(r.secret) == 0 || !hmac.Equal([]byte(expected), []byte(got)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var push CookiePush
+	if err := json.Unmarshal(body, &push); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Reject stale or replayed pushes. A replay carries the same signature,
+	// which only has to be remembered while its timestamp can still pass.
+	now := time.Now()
+	skew := now.Sub(time.Unix(push.Timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+
+	r.mu.Lock()
+	for sig, at := range r.seen {
+		if now.Sub(at) > 2*r.MaxSkew {
+			delete(r.seen, sig)
+		}
+	}
+	if _, replayed := r.seen[got]; skew > r.MaxSkew || replayed {
+		r.mu.Unlock()
+		http.Error(w, "stale push", http.StatusConflict)
+		return
+	}
+	if len(r.seen) >= maxSeenPushes {
+		r.mu.Unlock()
+		http.Error(w, "too many pushes", http.StatusTooManyRequests)
+		return
+	}
+	r.seen[got] = now
+	r.mu.Unlock()
+
+	if len(push.Cookies) == 0 {
+		http.Error(w, "no cookies in push", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.client.importCookies(push.Cookies, "extension"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	select {
+	case r.pushed <- struct{}{}:
+	default:
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package walmart
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReceiveCookiePush(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	secret := []byte("shared-secret")
+	receiver := client.ReceiveCookiePush(secret)
+
+	body, _ := json.Marshal(CookiePush{
+		Timestamp: time.Now().Unix(),
+		Cookies:   map[string]string{"CID": "pushed"},
+	})
+
+	tests := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{"missing signature", "", http.StatusUnauthorized},
+		{"wrong secret", SignCookiePush([]byte("other"), body), http.StatusUnauthorized},
+		{"valid", SignCookiePush(secret, body), http.StatusNoContent},
+		{"replayed", SignCookiePush(secret, body), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/push", bytes.NewReader(body))
+		if tt.signature != "" {
+			req.Header.Set(CookiePushSignatureHeader, tt.signature)
+		}
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, rec.Code)
+		}
+	}
+
+	cid := client.CookieStore.Get("CID")
+	if cid == nil || cid.Value != "pushed" || cid.Source != "extension" {
+		t.Errorf("Pushed cookie not imported: %+v", cid)
+	}
+}
+
+func TestReceiveCookiePushStale(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	secret := []byte("shared-secret")
+	receiver := client.ReceiveCookiePush(secret)
+
+	body, _ := json.Marshal(CookiePush{
+		Timestamp: time.Now().Add(-time.Hour).Unix(),
+		Cookies:   map[string]string{"CID": "old"},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/push", bytes.NewReader(body))
+	req.Header.Set(CookiePushSignatureHeader, SignCookiePush(secret, body))
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected stale push to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestReceiveCookiePushBackToBack(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	secret := []byte("shared-secret")
+	receiver := client.ReceiveCookiePush(secret)
+
+	// Both pushes land within the same second
+	now := time.Now().Unix()
+	for i, push := range []CookiePush{
+		{Timestamp: now, Nonce: "first", Cookies: map[string]string{"CID": "one"}},
+		{Timestamp: now, Nonce: "second", Cookies: map[string]string{"CID": "two"}},
+	} {
+		body, _ := json.Marshal(push)
+		req := httptest.NewRequest(http.MethodPost, "/push", bytes.NewReader(body))
+		req.Header.Set(CookiePushSignatureHeader, SignCookiePush(secret, body))
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Push %d: expected %d, got %d", i+1, http.StatusNoContent, rec.Code)
+		}
+	}
+
+	if cid := client.CookieStore.Get("CID"); cid == nil || cid.Value != "two" {
+		t.Errorf("Expected the second push to win, got %+v", cid)
+	}
+}
@@ -0,0 +1,136 @@
+package walmart
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ParityKind describes how the client's request differs from the browser's
+type ParityKind string
+
+const (
+	ParityMissing   ParityKind = "missing"   // Browser sent it, the client would not
+	ParityExtra     ParityKind = "extra"     // Client would send it, the browser did not
+	ParityDifferent ParityKind = "different" // Both send it with different values
+)
+
+// ParityDifference is one header or cookie that does not match the capture
+type ParityDifference struct {
+	Kind    ParityKind `json:"kind"`
+	Scope   string     `json:"scope"` // "header" or "cookie"
+	Name    string     `json:"name"`
+	Browser string     `json:"browser,omitempty"` // Header values only; cookie values are never reported
+	Client  string     `json:"client,omitempty"`
+}
+
+// ParityReport compares a browser capture with what the client would send
+type ParityReport struct {
+	Operation   string             `json:"operation"`
+	Differences []ParityDifference `json:"differences"`
+}
+
+// volatileHeaders change on every request, so only their presence is compared
+var volatileHeaders = map[string]bool{
+	"x-o-correlation-id":    true,
+	"wm_qos.correlation_id": true,
+	"traceparent":           true,
+}
+
+// DiagnoseRequest compares the headers and cookies a real browser sent in a
+// curl capture against what the client would send for the same operation
+func (c *WalmartClient) DiagnoseRequest(curlFile string) (*ParityReport, error) {
+	data, err := os.ReadFile(curlFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read curl file: %w", err)
+	}
+	curlCmd := string(data)
+
+	req, err := http.NewRequest("GET", "https://www.walmart.com/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	report := &ParityReport{}
+	for _, operation := range []string{OperationGetOrder, OperationPurchaseHistory} {
+		if strings.Contains(curlCmd, "/"+operation+"/") {
+			report.Operation = operation
+		}
+	}
+	if report.Operation == "" {
+		return nil, fmt.Errorf("curl capture is not a %s or %s request", OperationGetOrder, OperationPurchaseHistory)
+	}
+	c.setOperationHeaders(req, report.Operation)
+	c.setCookies(req)
+
+	clientHeaders := make(map[string]string)
+	for name := range req.Header {
+		clientHeaders[strings.ToLower(name)] = req.Header.Get(name)
+	}
+
+	browserCookies := extractCookiesFromCurl(curlCmd)
+	browserHeaders := extractHeadersFromCurl(curlCmd)
+	if header, ok := browserHeaders["cookie"]; ok {
+		for name, value := range parseCookieHeader(header) {
+			browserCookies[name] = value
+		}
+	}
+
+	report.Differences = append(report.Differences, compareParity("header", browserHeaders, clientHeaders, true)...)
+	report.Differences = append(report.Differences,
+		compareParity("cookie", browserCookies, parseCookieHeader(req.Header.Get("Cookie")), false)...)
+	return report, nil
+}
+
+func compareParity(scope string, browser, client map[string]string, showValues bool) []ParityDifference {
+	var diffs []ParityDifference
+	for name, browserValue := range browser {
+		clientValue, ok := client[name]
+		switch {
+		case scope == "header" && name == "cookie":
+			continue
+		case !ok:
+			diffs = append(diffs, ParityDifference{Kind: ParityMissing, Scope: scope, Name: name, Browser: browserValue})
+		case browserValue != clientValue && !(scope == "header" && volatileHeaders[name]):
+			diffs = append(diffs, ParityDifference{Kind: ParityDifferent, Scope: scope, Name: name, Browser: browserValue, Client: clientValue})
+		}
+	}
+	for name, clientValue := range client {
+		if _, ok := browser[name]; !ok && !(scope == "header" && name == "cookie") {
+			diffs = append(diffs, ParityDifference{Kind: ParityExtra, Scope: scope, Name: name, Client: clientValue})
+		}
+	}
+
+	if !showValues {
+		for i := range diffs {
+			diffs[i].Browser, diffs[i].Client = "", ""
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// String renders the report for a terminal
+func (r *ParityReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Request parity for %s: %d difference(s)\n", r.Operation, len(r.Differences))
+	for _, d := range r.Differences {
+		switch d.Kind {
+		case ParityDifferent:
+			fmt.Fprintf(&b, "  ~ %s %s: browser %q, client %q\n", d.Scope, d.Name, d.Browser, d.Client)
+		case ParityMissing:
+			fmt.Fprintf(&b, "  - %s %s (browser only)\n", d.Scope, d.Name)
+		case ParityExtra:
+			fmt.Fprintf(&b, "  + %s %s (client only)\n", d.Scope, d.Name)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package walmart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseRequest(t *testing.T) {
+	tempDir := t.TempDir()
+	curlContent := `curl 'https://www.walmart.com/orchestra/orders/graphql/getOrder/abc?variables=%7B%7D' \
+  -H 'accept: application/json' \
+  -H 'user-agent: Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0' \
+  -H 'x-o-correlation-id: browser-123' \
+  -H 'x-px-authorization: 3' \
+  -H 'cookie: CID=abc; bstc=xyz' \
+  -b 'SPID=spid'`
+
+	curlFile := filepath.Join(tempDir, "curl.txt")
+	_ = os.WriteFile(curlFile, []byte(curlContent), 0644)
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: tempDir})
+	client.CookieStore.Set("CID", &Cookie{Value: "stale"})
+	client.CookieStore.Set("SPID", &Cookie{Value: "spid"})
+
+	report, err := client.DiagnoseRequest(curlFile)
+	if err != nil {
+		t.Fatalf("DiagnoseRequest failed: %v", err)
+	}
+	if report.Operation != OperationGetOrder {
+		t.Errorf("Expected getOrder, got %s", report.Operation)
+	}
+
+	found := make(map[string]ParityDifference)
+	for _, d := range report.Differences {
+		found[d.Scope+":"+d.Name] = d
+	}
+
+	if d := found["header:user-agent"]; d.Kind != ParityDifferent {
+		t.Errorf("Expected user-agent difference, got %+v", d)
+	}
+	if d := found["header:x-px-authorization"]; d.Kind != ParityMissing {
+		t.Errorf("Expected browser-only header, got %+v", d)
+	}
+	if _, ok := found["header:x-o-correlation-id"]; ok {
+		t.Error("Volatile headers should only be compared by presence")
+	}
+	if d := found["header:x-o-platform"]; d.Kind != ParityExtra {
+		t.Errorf("Expected client-only header, got %+v", d)
+	}
+	if d := found["cookie:CID"]; d.Kind != ParityDifferent || d.Browser != "" {
+		t.Errorf("Expected CID difference without values, got %+v", d)
+	}
+	if d := found["cookie:bstc"]; d.Kind != ParityMissing {
+		t.Errorf("Expected missing bstc cookie, got %+v", d)
+	}
+	if _, ok := found["cookie:SPID"]; ok {
+		t.Error("Matching cookies should not be reported")
+	}
+
+	if out := report.String(); !strings.Contains(out, "getOrder") || strings.Contains(out, "stale") {
+		t.Errorf("Unexpected report output:\n%s", out)
+	}
+}
+
+func TestDiagnoseRequestUnknownOperation(t *testing.T) {
+	tempDir := t.TempDir()
+	curlFile := filepath.Join(tempDir, "curl.txt")
+	_ = os.WriteFile(curlFile, []byte(`curl 'https://www.walmart.com/'`), 0644)
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: tempDir})
+	if _, err := client.DiagnoseRequest(curlFile); err == nil {
+		t.Error("Expected error for unsupported operation")
+	}
+}
@@ -0,0 +1,243 @@
+package walmart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies an entry in an OrderDiff
+type ChangeKind string
+
+const (
+	ChangeStatus      ChangeKind = "status"       // Group status type changed
+	ChangeItemAdded   ChangeKind = "item_added"   // Item appears only in the newer fetch
+	ChangeItemRemoved ChangeKind = "item_removed" // Item appears only in the older fetch
+	ChangeQuantity    ChangeKind = "quantity"     // Item quantity changed
+	ChangeItemPrice   ChangeKind = "item_price"   // Item line price changed
+	ChangePrice       ChangeKind = "price"        // Order-level price field changed
+)
+
+// OrderChange is one difference between two fetches of an order
+type OrderChange struct {
+	Kind    ChangeKind `json:"kind"`
+	GroupID string     `json:"groupId,omitempty"`
+	ItemID  string     `json:"itemId,omitempty"`
+	Field   string     `json:"field,omitempty"`
+	Old     string     `json:"old,omitempty"`
+	New     string     `json:"new,omitempty"`
+}
+
+// OrderDiff is the structured difference between two fetches of an order
+type OrderDiff struct {
+	OrderID string        `json:"orderId"`
+	Changes []OrderChange `json:"changes"`
+}
+
+// HasChanges reports whether anything differs
+func (d *OrderDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// String renders the diff as human-readable lines for notifications
+func (d *OrderDiff) String() string {
+	if !d.HasChanges() {
+		return fmt.Sprintf("Order %s: no changes", d.OrderID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Order %s:", d.OrderID)
+	for _, change := range d.Changes {
+		b.WriteString("\n  - ")
+		switch change.Kind {
+		case ChangeStatus:
+			fmt.Fprintf(&b, "Status changed from %s to %s", change.Old, change.New)
+		case ChangeItemAdded:
+			fmt.Fprintf(&b, "Item added: %s", change.New)
+		case ChangeItemRemoved:
+			fmt.Fprintf(&b, "Item removed: %s", change.Old)
+		case ChangeQuantity:
+			fmt.Fprintf(&b, "Quantity of %s changed from %s to %s", change.ItemID, change.Old, change.New)
+		case ChangeItemPrice:
+			fmt.Fprintf(&b, "Price of %s changed from %s to %s", change.ItemID, change.Old, change.New)
+		case ChangePrice:
+			fmt.Fprintf(&b, "%s changed from %s to %s", change.Field, change.Old, change.New)
+		}
+	}
+	return b.String()
+}
+
+// DiffOrders compares two fetches of the same order. Either side may be nil,
+// in which case every item is reported as added or removed.
+func DiffOrders(before, after *Order) *OrderDiff {
+	diff := &OrderDiff{}
+	if after != nil {
+		diff.OrderID = after.ID
+	} else if before != nil {
+		diff.OrderID = before.ID
+	}
+
+	diff.Changes = append(diff.Changes, diffGroupStatus(before, after)...)
+	diff.Changes = append(diff.Changes, diffItems(before, after)...)
+	diff.Changes = append(diff.Changes, diffPriceDetails(before, after)...)
+	return diff
+}
+
+func diffGroupStatus(before, after *Order) []OrderChange {
+	oldStatus := make(map[string]string)
+	if before != nil {
+		for _, group := range before.Groups {
+			oldStatus[group.ID] = group.Status.StatusType
+		}
+	}
+
+	var changes []OrderChange
+	if after == nil {
+		return changes
+	}
+	for _, group := range after.Groups {
+		previous, ok := oldStatus[group.ID]
+		if ok && previous != group.Status.StatusType {
+			changes = append(changes, OrderChange{
+				Kind:    ChangeStatus,
+				GroupID: group.ID,
+				Old:     previous,
+				New:     group.Status.StatusType,
+			})
+		}
+	}
+	return changes
+}
+
+func diffItems(before, after *Order) []OrderChange {
+	oldItems := indexItems(before)
+	newItems := indexItems(after)
+
+	var changes []OrderChange
+	for _, key := range sortedKeys(oldItems) {
+		if _, ok := newItems[key]; !ok {
+			changes = append(changes, OrderChange{Kind: ChangeItemRemoved, GroupID: key.group, ItemID: key.item, Old: itemLabel(oldItems[key])})
+		}
+	}
+
+	for _, key := range sortedKeys(newItems) {
+		item := newItems[key]
+		previous, ok := oldItems[key]
+		if !ok {
+			changes = append(changes, OrderChange{Kind: ChangeItemAdded, GroupID: key.group, ItemID: key.item, New: itemLabel(item)})
+			continue
+		}
+
+		if previous.Quantity != item.Quantity {
+			changes = append(changes, OrderChange{
+				Kind:    ChangeQuantity,
+				GroupID: key.group,
+				ItemID:  key.item,
+				Old:     formatQuantity(previous.Quantity),
+				New:     formatQuantity(item.Quantity),
+			})
+		}
+
+		oldPrice, oldOK := previous.LinePrice()
+		newPrice, newOK := item.LinePrice()
+		if oldOK != newOK || oldPrice != newPrice {
+			changes = append(changes, OrderChange{
+				Kind:    ChangeItemPrice,
+				GroupID: key.group,
+				ItemID:  key.item,
+				Old:     formatOptionalMoney(oldPrice, oldOK),
+				New:     formatOptionalMoney(newPrice, newOK),
+			})
+		}
+	}
+	return changes
+}
+
+func diffPriceDetails(before, after *Order) []OrderChange {
+	fields := []struct {
+		name string
+		get  func(*OrderPriceDetails) *PriceLineItem
+	}{
+		{"Subtotal", func(p *OrderPriceDetails) *PriceLineItem { return p.SubTotal }},
+		{"Tax", func(p *OrderPriceDetails) *PriceLineItem { return p.TaxTotal }},
+		{"Driver tip", func(p *OrderPriceDetails) *PriceLineItem { return p.DriverTip }},
+		{"Savings", func(p *OrderPriceDetails) *PriceLineItem { return p.Savings }},
+		{"Grand total", func(p *OrderPriceDetails) *PriceLineItem { return p.GrandTotal }},
+	}
+
+	var changes []OrderChange
+	for _, field := range fields {
+		oldValue, oldOK := priceField(before, field.get)
+		newValue, newOK := priceField(after, field.get)
+		if oldOK != newOK || oldValue != newValue {
+			changes = append(changes, OrderChange{
+				Kind:  ChangePrice,
+				Field: field.name,
+				Old:   formatOptionalMoney(oldValue, oldOK),
+				New:   formatOptionalMoney(newValue, newOK),
+			})
+		}
+	}
+	return changes
+}
+
+// itemKey identifies an item within an order; item IDs are only unique
+// within their group
+type itemKey struct {
+	group, item string
+}
+
+func indexItems(order *Order) map[itemKey]OrderItem {
+	items := make(map[itemKey]OrderItem)
+	if order == nil {
+		return items
+	}
+	for _, group := range order.Groups {
+		for _, item := range group.Items {
+			items[itemKey{group.ID, item.ID}] = item
+		}
+	}
+	return items
+}
+
+func sortedKeys(items map[itemKey]OrderItem) []itemKey {
+	keys := make([]itemKey, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].item < keys[j].item
+	})
+	return keys
+}
+
+func itemLabel(item OrderItem) string {
+	if name := item.Name(); name != "" {
+		return name
+	}
+	return item.ID
+}
+
+func priceField(order *Order, get func(*OrderPriceDetails) *PriceLineItem) (float64, bool) {
+	if order == nil || order.PriceDetails == nil {
+		return 0, false
+	}
+	if line := get(order.PriceDetails); line != nil {
+		return line.Value, true
+	}
+	return 0, false
+}
+
+func formatQuantity(quantity float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", quantity), "0"), ".")
+}
+
+func formatOptionalMoney(value float64, ok bool) string {
+	if !ok {
+		return "none"
+	}
+	return fmt.Sprintf("$%.2f", value)
+}
@@ -0,0 +1,101 @@
+package walmart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffOrders(t *testing.T) {
+	before := &Order{
+		ID: "1",
+		Groups: []OrderGroup{{
+			ID:     "g1",
+			Status: GroupStatus{StatusType: "PROCESSING"},
+			Items: []OrderItem{
+				{ID: "a", Quantity: 1, ProductInfo: &ProductInfo{Name: "Milk"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 3.50}}},
+				{ID: "b", Quantity: 1, ProductInfo: &ProductInfo{Name: "Bread"}},
+			},
+		}},
+		PriceDetails: &OrderPriceDetails{GrandTotal: &PriceLineItem{Value: 5.00}},
+	}
+	after := &Order{
+		ID: "1",
+		Groups: []OrderGroup{{
+			ID:     "g1",
+			Status: GroupStatus{StatusType: "DELIVERED"},
+			Items: []OrderItem{
+				{ID: "a", Quantity: 2, ProductInfo: &ProductInfo{Name: "Milk"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 7.00}}},
+				{ID: "c", Quantity: 1, ProductInfo: &ProductInfo{Name: "Eggs"}},
+			},
+		}},
+		PriceDetails: &OrderPriceDetails{
+			GrandTotal: &PriceLineItem{Value: 9.00},
+			DriverTip:  &PriceLineItem{Value: 2.00},
+		},
+	}
+
+	diff := DiffOrders(before, after)
+
+	kinds := make(map[ChangeKind]int)
+	for _, change := range diff.Changes {
+		kinds[change.Kind]++
+	}
+
+	expected := map[ChangeKind]int{
+		ChangeStatus:      1,
+		ChangeItemAdded:   1,
+		ChangeItemRemoved: 1,
+		ChangeQuantity:    1,
+		ChangeItemPrice:   1,
+		ChangePrice:       2,
+	}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("Expected %d %s changes, got %d", count, kind, kinds[kind])
+		}
+	}
+
+	text := diff.String()
+	for _, want := range []string{"PROCESSING to DELIVERED", "Item added: Eggs", "Item removed: Bread", "from 1 to 2", "Driver tip changed from none to $2.00"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+
+	if DiffOrders(after, after).HasChanges() {
+		t.Error("Identical orders should have no changes")
+	}
+}
+
+func TestDiffOrdersKeepsGroupsApart(t *testing.T) {
+	// Item IDs restart in every group, so "0" in g1 and g2 are different lines
+	order := func(milkQty float64) *Order {
+		return &Order{
+			ID: "1",
+			Groups: []OrderGroup{
+				{ID: "g1", Items: []OrderItem{{ID: "0", Quantity: milkQty, ProductInfo: &ProductInfo{Name: "Milk"}}}},
+				{ID: "g2", Items: []OrderItem{{ID: "0", Quantity: 1, ProductInfo: &ProductInfo{Name: "Paper Towels"}}}},
+			},
+		}
+	}
+
+	if diff := DiffOrders(order(1), order(1)); diff.HasChanges() {
+		t.Errorf("Expected no changes, got:\n%s", diff)
+	}
+
+	diff := DiffOrders(order(1), order(2))
+	if len(diff.Changes) != 1 {
+		t.Fatalf("Expected 1 change, got:\n%s", diff)
+	}
+	change := diff.Changes[0]
+	if change.Kind != ChangeQuantity || change.GroupID != "g1" || change.ItemID != "0" {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+
+	removed := order(1)
+	removed.Groups[1].Items = nil
+	diff = DiffOrders(order(1), removed)
+	if len(diff.Changes) != 1 || diff.Changes[0].Kind != ChangeItemRemoved || diff.Changes[0].Old != "Paper Towels" {
+		t.Errorf("Expected only the g2 item removed, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,126 @@
+package walmart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors matched with errors.Is
+var (
+	ErrSessionExpired = errors.New("session expired")
+	ErrRateLimited    = errors.New("rate limited")
+	ErrBotChallenge   = errors.New("bot challenge")
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrStaleQueryHash = errors.New("stale query hash") // Walmart redeployed; see DiscoverQueryHashes
+)
+
+// APIError is a failed response from Walmart
+type APIError struct {
+	Operation     string        // e.g. "getOrder"
+	StatusCode    int           // HTTP status, or 200 when the payload itself was empty
+	CorrelationID string        // Correlation ID of the request, for support tickets
+	RetryAfter    time.Duration // From the Retry-After header, when given
+	Body          string        // Response body for unexpected statuses
+	Kind          error         // One of the sentinel errors, or nil
+}
+
+func (e *APIError) Error() string {
+	switch e.Kind {
+	case ErrRateLimited:
+		return "rate limited - cookies might be stale, try refreshing from browser"
+	case ErrSessionExpired, ErrBotChallenge:
+		return "access denied - cookies expired, please update from browser"
+	case ErrOrderNotFound:
+		return "no order data in response"
+	case ErrStaleQueryHash:
+		return fmt.Sprintf("%s query hash no longer accepted - run DiscoverQueryHashes or set QueryHashes", e.Operation)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Unwrap makes errors.Is match the sentinel in Kind
+func (e *APIError) Unwrap() error { return e.Kind }
+
+// newAPIError classifies a non-200 response. Missing orders come back as 200
+// with no order data, so ErrOrderNotFound is set by the caller instead.
+func newAPIError(operation string, req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Operation:     operation,
+		StatusCode:    resp.StatusCode,
+		CorrelationID: correlationID(req, resp),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		apiErr.Kind = ErrRateLimited
+		apiErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	case http.StatusForbidden:
+		apiErr.Kind = ErrSessionExpired
+	case http.StatusTeapot:
+		apiErr.Kind = ErrBotChallenge
+	case http.StatusNotFound:
+		// Unknown persisted queries are the only 404s the GraphQL endpoints give
+		apiErr.Kind = ErrStaleQueryHash
+	case http.StatusServiceUnavailable:
+		apiErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	if apiErr.Kind == nil {
+		apiErr.Body = string(body)
+	}
+	return apiErr
+}
+
+// persistedQueryNotFound reports whether a 200 body carries the GraphQL error
+// for an unknown query hash instead of data
+func persistedQueryNotFound(body []byte) bool {
+	var payload struct {
+		Errors []struct {
+			Message    string `json:"message"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return false
+	}
+	for _, e := range payload.Errors {
+		if e.Message == "PersistedQueryNotFound" || e.Extensions.Code == "PERSISTED_QUERY_NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
+
+// correlationID prefers the ID Walmart echoes back over the one we sent
+func correlationID(req *http.Request, resp *http.Response) string {
+	if resp != nil {
+		for _, name := range []string{"x-o-correlation-id", "wm_qos.correlation_id"} {
+			if id := resp.Header.Get(name); id != "" {
+				return id
+			}
+		}
+	}
+	if req != nil {
+		return req.Header.Get("x-o-correlation-id")
+	}
+	return ""
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package walmart
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestTypedAPIErrors(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderId": "EMPTY"}, `{"data":{"order":null}}`)
+	srv.Enqueue(
+		server.BotChallenge(),
+		server.RateLimited("5"),
+		server.Response{Status: http.StatusForbidden, Header: http.Header{"X-O-Correlation-Id": {"abc-123"}}},
+		server.Response{Status: http.StatusBadGateway, Body: "upstream down"},
+		server.Response{Status: http.StatusNotFound},
+	)
+
+	tests := []struct {
+		name   string
+		status int
+		kind   error
+		body   string
+	}{
+		{"bot challenge", http.StatusTeapot, ErrBotChallenge, ""},
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited, ""},
+		{"session expired", http.StatusForbidden, ErrSessionExpired, ""},
+		{"unexpected status", http.StatusBadGateway, nil, "upstream down"},
+		{"unknown query hash", http.StatusNotFound, ErrStaleQueryHash, ""},
+	}
+
+	for _, tt := range tests {
+		_, err := client.GetOrder("TEST123", true)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s: expected *APIError, got %v", tt.name, err)
+		}
+		if apiErr.StatusCode != tt.status || apiErr.Operation != OperationGetOrder {
+			t.Errorf("%s: unexpected error %+v", tt.name, apiErr)
+		}
+		if tt.kind != nil && !errors.Is(err, tt.kind) {
+			t.Errorf("%s: expected errors.Is %v, got %v", tt.name, tt.kind, err)
+		}
+		if apiErr.CorrelationID == "" {
+			t.Errorf("%s: expected a correlation ID", tt.name)
+		}
+
+		switch tt.kind {
+		case ErrRateLimited:
+			if apiErr.RetryAfter != 5*time.Second {
+				t.Errorf("Expected 5s Retry-After, got %s", apiErr.RetryAfter)
+			}
+		case ErrSessionExpired:
+			if apiErr.CorrelationID != "abc-123" {
+				t.Errorf("Expected the echoed correlation ID, got %q", apiErr.CorrelationID)
+			}
+		case nil:
+			if apiErr.Kind != nil || apiErr.Body != tt.body {
+				t.Errorf("%s: expected an unclassified error with the body, got %+v", tt.name, apiErr)
+			}
+		}
+	}
+
+	if _, err := client.GetOrder("EMPTY", true); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+
+	// Some gateways answer an unknown hash with 200 and a GraphQL error
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderId": "GONE"}, `{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+	if _, err := client.GetOrder("GONE", true); !errors.Is(err, ErrStaleQueryHash) {
+		t.Errorf("Expected ErrStaleQueryHash, got %v", err)
+	}
+}
+
+func TestSessionErrorsTriggerRefresh(t *testing.T) {
+	if !isSessionError(&APIError{Kind: ErrBotChallenge}) || !isSessionError(&APIError{Kind: ErrSessionExpired}) {
+		t.Error("Expected 403 and 418 to count as session errors")
+	}
+	if isSessionError(&APIError{Kind: ErrRateLimited}) {
+		t.Error("Rate limiting is not a session error")
+	}
+}
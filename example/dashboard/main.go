@@ -0,0 +1,91 @@
+// Command dashboard serves a small web page charting recent Walmart spend.
+//
+//	go run ./example/dashboard -addr localhost:8080 -orders 20
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+	"github.com/eshaffer321/walmart-client-go/analytics"
+)
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Walmart Spend</title>
+<script src="https://cdn.jsdelivr.net/npm/chart.js@4"></script>
+<style>body { font-family: sans-serif; max-width: 900px; margin: 2em auto; }</style>
+</head>
+<body>
+<h1>Walmart Spend</h1>
+<h2>Orders</h2>
+<canvas id="timeline"></canvas>
+<h2>By Month</h2>
+<canvas id="monthly"></canvas>
+<script>
+async function draw(id, url, type) {
+  const chart = await (await fetch(url)).json();
+  new Chart(document.getElementById(id), {
+    type: type,
+    data: {
+      labels: chart.labels,
+      datasets: chart.series.map(s => ({ label: s.name, data: s.data })),
+    },
+  });
+}
+draw("timeline", "/api/timeline", "line");
+draw("monthly", "/api/monthly", "bar");
+</script>
+</body>
+</html>
+`
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to serve on")
+	limit := flag.Int("orders", 20, "number of recent orders to chart")
+	flag.Parse()
+
+	client, err := walmart.NewWalmartClient(walmart.ClientConfig{RateLimit: 2 * time.Second})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	summaries, err := client.GetRecentOrders(*limit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var orders []*walmart.Order
+	for _, summary := range summaries {
+		order, err := client.GetOrder(summary.OrderID, summary.FulfillmentType == "IN_STORE")
+		if err != nil {
+			log.Printf("skipping order %s: %v", summary.OrderID, err)
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	serveJSON := func(v interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(v)
+		}
+	}
+
+	http.HandleFunc("/api/timeline", serveJSON(analytics.TimelineChart(orders)))
+	http.HandleFunc("/api/monthly", serveJSON(analytics.SpendChart(analytics.MonthlySpend(orders))))
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	})
+
+	log.Printf("Dashboard for %d orders at http://%s", len(orders), *addr)
+	server := &http.Server{Addr: *addr, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(server.ListenAndServe())
+}
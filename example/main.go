@@ -5,7 +5,7 @@ import (
 	"log"
 	"time"
 
-	walmart "github.com/eshaffer321/walmart-client"
+	walmart "github.com/eshaffer321/walmart-client-go"
 )
 
 func main() {
@@ -42,7 +42,9 @@ func main() {
 			log.Fatal(err)
 		}
 
-		fmt.Printf("Order Total: %s\n", fullOrder.PriceDetails.GrandTotal.DisplayValue)
+		if fullOrder.PriceDetails != nil && fullOrder.PriceDetails.GrandTotal != nil {
+			fmt.Printf("Order Total: %s\n", fullOrder.PriceDetails.GrandTotal.DisplayValue)
+		}
 		fmt.Printf("Items:\n")
 		for _, item := range fullOrder.GetItems() {
 			if item.ProductInfo != nil {
@@ -35,8 +35,10 @@ func ExampleJSON() {
 		}
 
 		// Access as struct
-		fmt.Printf("Order Total (as float): %.2f\n", order.PriceDetails.GrandTotal.Value)
-		fmt.Printf("Order Total (as string): %s\n", order.PriceDetails.GrandTotal.DisplayValue)
+		if order.PriceDetails != nil && order.PriceDetails.GrandTotal != nil {
+			fmt.Printf("Order Total (as float): %.2f\n", order.PriceDetails.GrandTotal.Value)
+			fmt.Printf("Order Total (as string): %s\n", order.PriceDetails.GrandTotal.DisplayValue)
+		}
 
 		// Or convert whole order to JSON
 		orderJSON, err := json.MarshalIndent(order, "", "  ")
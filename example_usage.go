@@ -37,7 +37,7 @@ func ExampleUsage() {
 		}
 
 		// Access pricing data
-		if fullOrder.PriceDetails != nil {
+		if fullOrder.PriceDetails != nil && fullOrder.PriceDetails.GrandTotal != nil {
 			fmt.Printf("Order Total: %s\n", fullOrder.PriceDetails.GrandTotal.DisplayValue)
 		}
 
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func init() {
+	Register("exec", func(settings map[string]string) (Exporter, error) {
+		e := &Exec{Command: strings.Fields(settings["command"])}
+		if len(e.Command) == 0 {
+			return nil, errors.New("command is required")
+		}
+		if timeout := settings["timeout"]; timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout: %w", err)
+			}
+			e.Timeout = d
+		}
+		return e, nil
+	})
+}
+
+// Exec hands receipts to an external program, so a destination can be
+// written in any language without rebuilding against this module. The
+// program reads a JSON array of walmart.BasicReceipt values on stdin and
+// reports failure with a non-zero exit status.
+type Exec struct {
+	Command []string
+	Timeout time.Duration // Kills the program after this long; 0 waits
+}
+
+// Export implements Exporter
+func (e *Exec) Export(receipts []walmart.Receipt) error {
+	if len(e.Command) == 0 {
+		return fmt.Errorf("no command configured")
+	}
+
+	records := make([]walmart.BasicReceipt, len(receipts))
+	for i, r := range receipts {
+		records[i] = toBasic(r)
+	}
+	input, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...) //nolint:gosec // commands come from the caller's configuration
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second // Don't wait on children still holding stderr after a kill
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", e.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
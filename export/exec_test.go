@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "receipts.json")
+	total := 4.98
+	receipts := []walmart.Receipt{&walmart.BasicReceipt{
+		ID:     "R1",
+		Source: "email",
+		Time:   time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Total:  &total,
+		Lines:  []walmart.ReceiptLine{{Name: "Soda", Quantity: 1, Amount: 4.98}},
+	}}
+
+	exporter := &Exec{Command: []string{"sh", "-c", `cat > "$0"`, path}}
+	if err := exporter.Export(receipts); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []walmart.BasicReceipt
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Expected JSON on stdin, got %s: %v", data, err)
+	}
+	if len(got) != 1 || got[0].ID != "R1" || *got[0].Total != 4.98 || got[0].Lines[0].Name != "Soda" {
+		t.Errorf("Unexpected receipts: %+v", got)
+	}
+}
+
+func TestExecFailures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	exporter, err := New("exec", map[string]string{"command": "sh -c exit", "timeout": "5s"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := exporter.Export(nil); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+
+	failing := &Exec{Command: []string{"sh", "-c", "echo rejected >&2; exit 3"}}
+	if err := failing.Export(nil); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("Expected stderr in the error, got %v", err)
+	}
+
+	slow := &Exec{Command: []string{"sh", "-c", "sleep 5"}, Timeout: 50 * time.Millisecond}
+	if err := slow.Export(nil); err == nil {
+		t.Error("Expected the timeout to kill the program")
+	}
+
+	if _, err := New("exec", map[string]string{}); err == nil {
+		t.Error("Expected a missing command to be rejected")
+	}
+}
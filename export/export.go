@@ -0,0 +1,100 @@
+// Package export sends receipts to destinations such as budgeting apps.
+// Exporters register by name, so a destination added in another module is
+// selected the same way as a built-in one.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// Exporter delivers receipts to one destination. Implementations should
+// skip receipts they already delivered, so runs can overlap.
+type Exporter interface {
+	Export(receipts []walmart.Receipt) error
+}
+
+// ExporterFunc adapts a function into an Exporter
+type ExporterFunc func(receipts []walmart.Receipt) error
+
+// Export calls f
+func (f ExporterFunc) Export(receipts []walmart.Receipt) error {
+	return f(receipts)
+}
+
+// Factory builds an Exporter from string settings, e.g. read from a config
+// file or environment variables
+type Factory func(settings map[string]string) (Exporter, error)
+
+// ErrUnknownExporter is returned by New for names nobody registered
+var ErrUnknownExporter = errors.New("unknown exporter")
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes an exporter available to New. Like database/sql drivers,
+// it is meant to be called from an init function, and panics if name is
+// empty or already taken.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || factory == nil {
+		panic("export: Register needs a name and a factory")
+	}
+	if _, ok := registry[name]; ok {
+		panic("export: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// New builds the exporter registered under name
+func New(name string, settings map[string]string) (Exporter, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, name)
+	}
+
+	exporter, err := factory(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure %s exporter: %w", name, err)
+	}
+	return exporter, nil
+}
+
+// Names lists the registered exporters in order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toBasic copies a receipt into its serializable form
+func toBasic(r walmart.Receipt) walmart.BasicReceipt {
+	basic := walmart.BasicReceipt{
+		ID:     r.ReceiptID(),
+		Source: r.ReceiptSource(),
+		Lines:  r.ReceiptLines(),
+	}
+	if t, err := r.OrderTime(); err == nil {
+		basic.Time = t
+	}
+	if total, ok := r.ChargedTotal(); ok {
+		basic.Total = &total
+	}
+	return basic
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"errors"
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestRegistry(t *testing.T) {
+	var got []walmart.Receipt
+	Register("test-memory", func(settings map[string]string) (Exporter, error) {
+		if settings["fail"] != "" {
+			return nil, errors.New("bad settings")
+		}
+		return ExporterFunc(func(receipts []walmart.Receipt) error {
+			got = append(got, receipts...)
+			return nil
+		}), nil
+	})
+
+	names := Names()
+	if len(names) != 3 || names[0] != "exec" || names[1] != "firefly" || names[2] != "test-memory" {
+		t.Errorf("Unexpected registered exporters: %v", names)
+	}
+
+	exporter, err := New("test-memory", nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	order := &walmart.Order{ID: "111"}
+	if err := exporter.Export(walmart.OrdersAsReceipts([]*walmart.Order{order})); err != nil || len(got) != 1 {
+		t.Errorf("Expected the receipt to be exported, got %v %v", got, err)
+	}
+
+	if _, err := New("test-memory", map[string]string{"fail": "1"}); err == nil {
+		t.Error("Expected factory errors to be returned")
+	}
+	if _, err := New("missing", nil); !errors.Is(err, ErrUnknownExporter) {
+		t.Errorf("Expected ErrUnknownExporter, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a duplicate registration to panic")
+		}
+	}()
+	Register("exec", nil)
+}
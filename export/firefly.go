@@ -0,0 +1,269 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+const (
+	orderURLPrefix      = "https://www.walmart.com/orders/"
+	defaultDestination  = "Walmart"
+	defaultFeesCategory = "Tax and fees"
+)
+
+func init() {
+	Register("firefly", func(settings map[string]string) (Exporter, error) {
+		f := &Firefly{
+			BaseURL:       settings["url"],
+			Token:         settings["token"],
+			SourceAccount: settings["source_account"],
+			Destination:   settings["destination_account"],
+			Category:      settings["category"],
+		}
+		if f.BaseURL == "" || f.Token == "" || f.SourceAccount == "" {
+			return nil, errors.New("url, token and source_account are required")
+		}
+		return f, nil
+	})
+}
+
+// Firefly creates one Firefly III withdrawal per receipt through its REST
+// API, split by category, with a link to the order in the notes. Receipts
+// Firefly already has are skipped using its duplicate detection.
+type Firefly struct {
+	BaseURL       string // e.g. https://firefly.example.com
+	Token         string // Personal access token
+	SourceAccount string // Asset account the orders were paid from
+	Destination   string // Expense account; defaults to "Walmart"
+
+	// Categorize names the category of a line; nil puts every line in
+	// Category. Orders carry no Walmart category to default to.
+	Categorize func(line walmart.ReceiptLine) string
+	Category   string
+
+	// FeesCategory receives tax and fees: the charged total beyond the lines.
+	// Defaults to "Tax and fees".
+	FeesCategory string
+
+	HTTPClient *http.Client // Defaults to a client with a 30 second timeout
+}
+
+type fireflyTransaction struct {
+	Type            string   `json:"type"`
+	Date            string   `json:"date"`
+	Amount          string   `json:"amount"`
+	Description     string   `json:"description"`
+	SourceName      string   `json:"source_name"`
+	DestinationName string   `json:"destination_name"`
+	CategoryName    string   `json:"category_name,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+	ExternalID      string   `json:"external_id"`
+	ExternalURL     string   `json:"external_url,omitempty"`
+	Tags            []string `json:"tags"`
+}
+
+type fireflyGroup struct {
+	ErrorIfDuplicateHash bool                 `json:"error_if_duplicate_hash"`
+	ApplyRules           bool                 `json:"apply_rules"`
+	GroupTitle           string               `json:"group_title,omitempty"`
+	Transactions         []fireflyTransaction `json:"transactions"`
+}
+
+// Export implements Exporter
+func (f *Firefly) Export(receipts []walmart.Receipt) error {
+	for _, r := range receipts {
+		group, err := f.group(r)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", r.ReceiptID(), err)
+		}
+		if group == nil {
+			continue
+		}
+		if err := f.post(group); err != nil {
+			return fmt.Errorf("failed to export %s: %w", r.ReceiptID(), err)
+		}
+	}
+	return nil
+}
+
+// group builds the split transaction for r, or nil when there is nothing
+// to book
+func (f *Firefly) group(r walmart.Receipt) (*fireflyGroup, error) {
+	date, err := r.OrderTime()
+	if err != nil {
+		return nil, err
+	}
+
+	type split struct {
+		amount float64
+		lines  []string
+	}
+	splits := make(map[string]*split)
+	var categories []string
+	add := func(category string, amount float64, line string) {
+		s, ok := splits[category]
+		if !ok {
+			s = &split{}
+			splits[category] = s
+			categories = append(categories, category)
+		}
+		s.amount += amount
+		if line != "" {
+			s.lines = append(s.lines, line)
+		}
+	}
+
+	itemTotal := 0.0
+	for _, line := range r.ReceiptLines() {
+		category := f.Category
+		if f.Categorize != nil {
+			category = f.Categorize(line)
+		}
+		add(category, line.Amount, fmt.Sprintf("%s x%g: %.2f", line.Name, line.Quantity, line.Amount))
+		itemTotal += line.Amount
+	}
+
+	// Tax and fees go in their own split; savings come off the largest one
+	if total, ok := r.ChargedTotal(); ok {
+		if extra := roundCents(total - itemTotal); extra > 0 {
+			add(f.feesCategory(), extra, "")
+		} else if extra < 0 && len(categories) > 0 {
+			largest := categories[0]
+			for _, category := range categories {
+				if splits[category].amount > splits[largest].amount {
+					largest = category
+				}
+			}
+			splits[largest].amount += extra
+		}
+	}
+
+	id := r.ReceiptID()
+	link := ""
+	if r.ReceiptSource() == walmart.ReceiptSourceAPI {
+		link = orderURLPrefix + id
+	}
+	destination := f.Destination
+	if destination == "" {
+		destination = defaultDestination
+	}
+
+	group := &fireflyGroup{
+		ErrorIfDuplicateHash: true,
+		ApplyRules:           true,
+		GroupTitle:           "Walmart order " + id,
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		s := splits[category]
+		amount := roundCents(s.amount)
+		if amount <= 0 {
+			continue
+		}
+		description := "Walmart order " + id
+		if category != "" {
+			description += " - " + category
+		}
+		notes := strings.Join(s.lines, "\n")
+		if link != "" {
+			notes = strings.TrimSpace(link + "\n" + notes)
+		}
+		group.Transactions = append(group.Transactions, fireflyTransaction{
+			Type:            "withdrawal",
+			Date:            date.Format(time.RFC3339),
+			Amount:          fmt.Sprintf("%.2f", amount),
+			Description:     description,
+			SourceName:      f.SourceAccount,
+			DestinationName: destination,
+			CategoryName:    category,
+			Notes:           notes,
+			ExternalID:      fmt.Sprintf("%s-%d", id, len(group.Transactions)+1),
+			ExternalURL:     link,
+			Tags:            []string{"walmart"},
+		})
+	}
+	if len(group.Transactions) == 0 {
+		return nil, nil
+	}
+	return group, nil
+}
+
+func (f *Firefly) post(group *fireflyGroup) error {
+	body, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimSuffix(f.BaseURL, "/")+"/api/v1/transactions", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.Token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := f.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnprocessableEntity && fireflyDuplicate(respBody):
+		return nil // Exported on an earlier run
+	}
+	return fmt.Errorf("firefly returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// fireflyDuplicate reports whether a validation error payload rejects the
+// group only because Firefly already has it
+func fireflyDuplicate(body []byte) bool {
+	var payload struct {
+		Message string              `json:"message"`
+		Errors  map[string][]string `json:"errors"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return false
+	}
+	isDuplicate := func(msg string) bool { return strings.HasPrefix(msg, "Duplicate of transaction") }
+
+	if len(payload.Errors) == 0 {
+		return isDuplicate(payload.Message)
+	}
+	for _, messages := range payload.Errors {
+		for _, msg := range messages {
+			if !isDuplicate(msg) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *Firefly) feesCategory() string {
+	if f.FeesCategory != "" {
+		return f.FeesCategory
+	}
+	return defaultFeesCategory
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+func TestFireflyExport(t *testing.T) {
+	var groups []fireflyGroup
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/transactions" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		var group fireflyGroup
+		if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+			t.Fatal(err)
+		}
+		groups = append(groups, group)
+		if len(groups) == 2 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"The given data was invalid.","errors":{"transactions.0.description":["Duplicate of transaction #12."]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	order := &walmart.Order{
+		ID:           "111",
+		OrderDate:    "2024-03-10T12:00:00.000-0700",
+		PriceDetails: &walmart.OrderPriceDetails{GrandTotal: &walmart.PriceLineItem{Value: 12.00}},
+		Groups: []walmart.OrderGroup{{Items: []walmart.OrderItem{
+			{Quantity: 2, ProductInfo: &walmart.ProductInfo{Name: "Milk"}, PriceInfo: &walmart.ItemPrice{LinePrice: &walmart.Price{Value: 6.00}}},
+			{Quantity: 1, ProductInfo: &walmart.ProductInfo{Name: "Paper Towels"}, PriceInfo: &walmart.ItemPrice{LinePrice: &walmart.Price{Value: 5.00}}},
+		}}},
+	}
+
+	exporter := &Firefly{
+		BaseURL:       srv.URL + "/",
+		Token:         "secret",
+		SourceAccount: "Checking",
+		Categorize: func(line walmart.ReceiptLine) string {
+			if line.Name == "Milk" {
+				return "Groceries"
+			}
+			return "Household"
+		},
+	}
+	receipts := walmart.OrdersAsReceipts([]*walmart.Order{order})
+	if err := exporter.Export(receipts); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if err := exporter.Export(receipts); err != nil {
+		t.Fatalf("Expected a duplicate to be skipped, got %v", err)
+	}
+
+	splits := groups[0].Transactions
+	if len(splits) != 3 {
+		t.Fatalf("Expected groceries, household and fee splits, got %+v", splits)
+	}
+	want := map[string]string{"Groceries": "6.00", "Household": "5.00", "Tax and fees": "1.00"}
+	for _, split := range splits {
+		if want[split.CategoryName] != split.Amount {
+			t.Errorf("Unexpected split %s: %s", split.CategoryName, split.Amount)
+		}
+		if !strings.HasPrefix(split.Notes, "https://www.walmart.com/orders/111") {
+			t.Errorf("Expected the order link in the notes, got %q", split.Notes)
+		}
+		if split.SourceName != "Checking" || split.DestinationName != "Walmart" || split.Date != "2024-03-10T12:00:00-07:00" {
+			t.Errorf("Unexpected split: %+v", split)
+		}
+	}
+	if !strings.Contains(splits[0].Notes, "Milk x2: 6.00") {
+		t.Errorf("Expected item lines in the notes, got %q", splits[0].Notes)
+	}
+	seen := make(map[string]bool)
+	for _, split := range splits {
+		if !strings.HasPrefix(split.ExternalID, "111-") || seen[split.ExternalID] {
+			t.Errorf("Expected a distinct external ID per split, got %q", split.ExternalID)
+		}
+		seen[split.ExternalID] = true
+	}
+}
+
+func TestFireflyDuplicateDetection(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{`{"message":"The given data was invalid.","errors":{"transactions.0.description":["Duplicate of transaction #12."]}}`, true},
+		{`{"message":"Duplicate of transaction #12."}`, true},
+		{`{"message":"The given data was invalid.","errors":{"transactions.0.amount":["Amount is invalid."],"transactions.1.description":["Duplicate of transaction #12."]}}`, false},
+		{`<html>Duplicate of transaction #12 is not what happened here</html>`, false},
+	}
+	for _, tt := range tests {
+		if got := fireflyDuplicate([]byte(tt.body)); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.body, tt.want, got)
+		}
+	}
+}
+
+func TestFireflyErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Unauthenticated."}`))
+	}))
+	defer srv.Close()
+
+	exporter, err := New("firefly", map[string]string{"url": srv.URL, "token": "bad", "source_account": "Checking"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	total := 3.50
+	receipts := []walmart.Receipt{&walmart.BasicReceipt{ID: "R1", Source: "email", Total: &total}}
+	if err := exporter.Export(receipts); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+
+	if _, err := New("firefly", map[string]string{"url": srv.URL}); err == nil {
+		t.Error("Expected missing settings to be rejected")
+	}
+}
@@ -0,0 +1,135 @@
+package walmart
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FingerprintPolicy controls what happens when requests would be sent with a
+// different header profile than the one the cookies were captured with
+type FingerprintPolicy int
+
+const (
+	// FingerprintWarn logs the mismatch once and sends the request anyway
+	FingerprintWarn FingerprintPolicy = iota
+	// FingerprintIgnore skips the check entirely
+	FingerprintIgnore
+	// FingerprintEnforce refuses to send mismatched requests
+	FingerprintEnforce
+)
+
+// fingerprintHeaders are the headers bot detection ties to a session
+var fingerprintHeaders = []string{
+	"user-agent",
+	"accept-language",
+	"sec-ch-ua",
+	"sec-ch-ua-mobile",
+	"sec-ch-ua-platform",
+	"x-o-platform-version",
+}
+
+// SessionFingerprint is the header profile a cookie jar was created with
+type SessionFingerprint struct {
+	Headers map[string]string `json:"headers"`
+	Source  string            `json:"source"` // Where the profile was captured, e.g. "curl"
+}
+
+// ErrFingerprintMismatch is returned under FingerprintEnforce
+var ErrFingerprintMismatch = errors.New("request header profile does not match the session fingerprint")
+
+// FingerprintMismatch describes one header that differs from the pinned profile
+type FingerprintMismatch struct {
+	Header   string
+	Pinned   string
+	Outgoing string
+}
+
+// newFingerprint keeps only the profile headers from a captured request
+func newFingerprint(headers map[string]string, source string) *SessionFingerprint {
+	profile := make(map[string]string)
+	for _, name := range fingerprintHeaders {
+		if value, ok := headers[name]; ok {
+			profile[name] = value
+		}
+	}
+	if len(profile) == 0 {
+		return nil
+	}
+	return &SessionFingerprint{Headers: profile, Source: source}
+}
+
+// Compare lists headers the outgoing request sends with a different value
+func (f *SessionFingerprint) Compare(header http.Header) []FingerprintMismatch {
+	if f == nil {
+		return nil
+	}
+
+	var mismatches []FingerprintMismatch
+	for name, pinned := range f.Headers {
+		outgoing := header.Get(name)
+		if outgoing != "" && outgoing != pinned {
+			mismatches = append(mismatches, FingerprintMismatch{
+				Header:   name,
+				Pinned:   pinned,
+				Outgoing: outgoing,
+			})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Header < mismatches[j].Header })
+	return mismatches
+}
+
+// checkFingerprint applies the configured policy to an outgoing request
+func (c *WalmartClient) checkFingerprint(req *http.Request) error {
+	if c.fingerprintPolicy == FingerprintIgnore {
+		return nil
+	}
+
+	c.CookieStore.mu.RLock()
+	fingerprint := c.CookieStore.Fingerprint
+	c.CookieStore.mu.RUnlock()
+
+	mismatches := fingerprint.Compare(req.Header)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		names[i] = m.Header
+	}
+
+	if c.fingerprintPolicy == FingerprintEnforce {
+		return fmt.Errorf("%w: %s", ErrFingerprintMismatch, strings.Join(names, ", "))
+	}
+
+	c.fingerprintWarn.Do(func() {
+		c.logger.Warn("outgoing headers differ from the browser the cookies came from; this can trigger bot detection",
+			"headers", strings.Join(names, ", "))
+	})
+	return nil
+}
+
+func extractHeadersFromCurl(curlCmd string) map[string]string {
+	headers := make(map[string]string)
+	lines := strings.Split(curlCmd, "\\\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-H '") || strings.HasPrefix(line, "--header '") {
+			start := strings.Index(line, "'") + 1
+			end := strings.LastIndex(line, "'")
+			if start > 0 && end > start {
+				parts := strings.SplitN(line[start:end], ":", 2)
+				if len(parts) == 2 {
+					headers[strings.ToLower(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+				}
+			}
+		}
+	}
+	return headers
+}
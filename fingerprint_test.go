@@ -0,0 +1,64 @@
+package walmart
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInitializeFromCurlPinsFingerprint(t *testing.T) {
+	tempDir := t.TempDir()
+	curlContent := `curl 'https://www.walmart.com/orchestra/orders/graphql/getOrder/abc' \
+  -H 'accept: application/json' \
+  -H 'User-Agent: Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0' \
+  -H 'x-o-platform-version: usweb-1.230.0' \
+  -b 'CID=test_cid'`
+
+	curlFile := filepath.Join(tempDir, "curl.txt")
+	_ = os.WriteFile(curlFile, []byte(curlContent), 0644)
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: tempDir, FingerprintPolicy: FingerprintEnforce})
+	if err := client.InitializeFromCurl(curlFile); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	fp := client.CookieStore.Fingerprint
+	if fp == nil {
+		t.Fatal("Fingerprint was not pinned")
+	}
+	if fp.Headers["user-agent"] != "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0" {
+		t.Errorf("Unexpected pinned user-agent: %q", fp.Headers["user-agent"])
+	}
+	if _, ok := fp.Headers["accept"]; ok {
+		t.Error("Non-profile headers should not be pinned")
+	}
+
+	req, _ := http.NewRequest("GET", "https://www.walmart.com", nil)
+	client.setHeaders(req)
+
+	err := client.checkFingerprint(req)
+	if !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("Expected ErrFingerprintMismatch, got %v", err)
+	}
+
+	// Matching profile passes
+	req.Header.Set("user-agent", fp.Headers["user-agent"])
+	req.Header.Set("x-o-platform-version", fp.Headers["x-o-platform-version"])
+	if err := client.checkFingerprint(req); err != nil {
+		t.Errorf("Expected matching profile to pass, got %v", err)
+	}
+}
+
+func TestFingerprintWarnDoesNotFail(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	client.CookieStore.Fingerprint = newFingerprint(map[string]string{"user-agent": "pinned"}, "curl")
+
+	req, _ := http.NewRequest("GET", "https://www.walmart.com", nil)
+	req.Header.Set("user-agent", "different")
+
+	if err := client.checkFingerprint(req); err != nil {
+		t.Errorf("Warn policy should not fail requests, got %v", err)
+	}
+}
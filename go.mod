@@ -1,3 +1,3 @@
-module github.com/eshaffer321/walmart-client
+module github.com/eshaffer321/walmart-client-go
 
-go 1.20
+go 1.21
@@ -0,0 +1,79 @@
+package walmart
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// volatileFields change between fetches without the order itself changing:
+// client-computed totals, CDN image URLs and human-readable status text
+var volatileFields = []string{"totalWithTip", "thumbnailUrl", "message", "deliveryMessage"}
+
+// ContentHash returns a stable SHA-256 over the JSON form of v with the
+// named fields removed at every depth. Object keys are hashed in sorted
+// order, so the hash only changes when the data does.
+func ContentHash(v interface{}, ignoreFields ...string) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return "", err
+	}
+
+	ignore := make(map[string]bool, len(ignoreFields))
+	for _, field := range ignoreFields {
+		ignore[field] = true
+	}
+
+	canonical, err := json.Marshal(stripFields(generic, ignore))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func stripFields(v interface{}, ignore map[string]bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ignore[key] {
+				delete(value, key)
+				continue
+			}
+			value[key] = stripFields(child, ignore)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = stripFields(child, ignore)
+		}
+	}
+	return v
+}
+
+// ContentHash identifies the order's content, ignoring volatile fields
+func (o *Order) ContentHash() string {
+	hash, _ := ContentHash(o, volatileFields...)
+	return hash
+}
+
+// ContentHash identifies the item's content, ignoring volatile fields
+func (i OrderItem) ContentHash() string {
+	hash, _ := ContentHash(i, volatileFields...)
+	return hash
+}
+
+// ContentHash identifies the history entry's content, ignoring volatile fields
+func (s OrderSummary) ContentHash() string {
+	hash, _ := ContentHash(s, volatileFields...)
+	return hash
+}
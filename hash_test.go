@@ -0,0 +1,40 @@
+package walmart
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderContentHash(t *testing.T) {
+	payloadA := `{"id":"1","displayId":"D1","groups_2101":[{"items":[{"id":"a","quantity":1,
+		"productInfo":{"name":"Milk","imageInfo":{"thumbnailUrl":"https://x/1.jpg?odnHeight=100"}}}],
+		"status":{"statusType":"DELIVERED","message":{"parts":[{"text":"Delivered today"}]}}}]}`
+	// Same order fetched later: keys reordered, volatile text and image URL changed
+	payloadB := `{"displayId":"D1","groups_2101":[{"status":{"message":{"parts":[{"text":"Delivered Sep 5"}]},
+		"statusType":"DELIVERED"},"items":[{"productInfo":{"imageInfo":{"thumbnailUrl":"https://x/1.jpg?odnHeight=200"},
+		"name":"Milk"},"quantity":1,"id":"a"}]}],"id":"1"}`
+
+	var a, b Order
+	_ = json.Unmarshal([]byte(payloadA), &a)
+	_ = json.Unmarshal([]byte(payloadB), &b)
+
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("Re-fetched order with only volatile changes should hash the same")
+	}
+
+	b.Groups[0].Items[0].Quantity = 2
+	if a.ContentHash() == b.ContentHash() {
+		t.Error("Quantity change should change the hash")
+	}
+	if a.Groups[0].Items[0].ContentHash() == b.Groups[0].Items[0].ContentHash() {
+		t.Error("Quantity change should change the item hash")
+	}
+}
+
+func TestContentHashIgnoreFields(t *testing.T) {
+	h1, _ := ContentHash(map[string]interface{}{"a": 1, "seen": "monday"}, "seen")
+	h2, _ := ContentHash(map[string]interface{}{"a": 1, "seen": "tuesday"}, "seen")
+	if h1 != h2 {
+		t.Error("Ignored fields should not affect the hash")
+	}
+}
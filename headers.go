@@ -0,0 +1,131 @@
+package walmart
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HeaderProfile is the browser identity requests claim: user-agent, client
+// hints and other headers bot detection ties to a session
+type HeaderProfile struct {
+	Name    string            `json:"name"`
+	Headers map[string]string `json:"headers"`
+}
+
+// DesktopChrome is Chrome on macOS, the default profile
+func DesktopChrome() HeaderProfile {
+	return HeaderProfile{
+		Name: "desktop-chrome",
+		Headers: map[string]string{
+			"user-agent":           "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
+			"accept-language":      "en-US",
+			"dnt":                  "1",
+			"x-o-platform-version": "usweb-1.221.0",
+		},
+	}
+}
+
+// MobileSafari is Safari on iPhone
+func MobileSafari() HeaderProfile {
+	return HeaderProfile{
+		Name: "mobile-safari",
+		Headers: map[string]string{
+			"user-agent":           "Mozilla/5.0 (iPhone; CPU iPhone OS 17_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Mobile/15E148 Safari/604.1",
+			"accept-language":      "en-US,en;q=0.9",
+			"x-o-platform-version": "usweb-1.221.0",
+		},
+	}
+}
+
+// MobileChrome is Chrome on Android. The Walmart app itself talks to
+// different endpoints, so there is no app profile.
+func MobileChrome() HeaderProfile {
+	return HeaderProfile{
+		Name: "mobile-chrome",
+		Headers: map[string]string{
+			"user-agent":           "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Mobile Safari/537.36",
+			"accept-language":      "en-US,en;q=0.9",
+			"sec-ch-ua-mobile":     "?1",
+			"sec-ch-ua-platform":   `"Android"`,
+			"x-o-platform-version": "usweb-1.221.0",
+		},
+	}
+}
+
+// SetHeaderProfile switches the profile for subsequent requests. Walmart
+// ties cookies to the browser they came from, so rotate profiles together
+// with the cookies rather than between requests of one session.
+func (c *WalmartClient) SetHeaderProfile(profile HeaderProfile) {
+	c.headerMu.Lock()
+	defer c.headerMu.Unlock()
+	c.headerProfile = profile
+}
+
+// HeaderProfile returns the profile requests are currently sent with
+func (c *WalmartClient) HeaderProfile() HeaderProfile {
+	c.headerMu.RLock()
+	defer c.headerMu.RUnlock()
+	return c.headerProfile
+}
+
+// setOperationHeaders sets the GraphQL headers for operation, then the
+// header profile, then any overrides
+func (c *WalmartClient) setOperationHeaders(req *http.Request, operation string) {
+	correlationID := fmt.Sprintf("walmart-go-%d", time.Now().Unix())
+	headers := map[string]string{
+		"accept":                  "application/json",
+		"content-type":            "application/json",
+		"x-apollo-operation-name": operation,
+		"x-o-gql-query":           "query " + operation,
+		"x-o-platform":            "rweb",
+		"x-o-bu":                  "WALMART-US",
+		"x-o-mart":                "B2C",
+		"x-o-segment":             "oaoh",
+		"x-o-correlation-id":      correlationID,
+		"wm_qos.correlation_id":   correlationID,
+		"wm_mp":                   "true",
+		"sec-fetch-site":          "same-origin",
+		"sec-fetch-mode":          "cors",
+		"sec-fetch-dest":          "empty",
+		"x-enable-server-timing":  "1",
+		"x-latency-trace":         "1",
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	c.setProfileHeaders(req)
+}
+
+// setDocumentHeaders sets the headers of a browser loading a page: no
+// GraphQL or Walmart API headers, then the header profile and overrides
+func (c *WalmartClient) setDocumentHeaders(req *http.Request) {
+	headers := map[string]string{
+		"accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"sec-fetch-site":            "same-origin",
+		"sec-fetch-mode":            "navigate",
+		"sec-fetch-dest":            "document",
+		"sec-fetch-user":            "?1",
+		"upgrade-insecure-requests": "1",
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	c.setProfileHeaders(req)
+}
+
+// setProfileHeaders applies the header profile, then any overrides
+func (c *WalmartClient) setProfileHeaders(req *http.Request) {
+	c.headerMu.RLock()
+	defer c.headerMu.RUnlock()
+	for k, v := range c.headerProfile.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range c.headerOverrides {
+		if v == "" {
+			req.Header.Del(k)
+		} else {
+			req.Header.Set(k, v)
+		}
+	}
+}
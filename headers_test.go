@@ -0,0 +1,44 @@
+package walmart
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHeaderProfiles(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	req, _ := http.NewRequest("GET", "https://www.walmart.com/", nil)
+	client.setHeaders(req)
+	if !strings.Contains(req.Header.Get("user-agent"), "Macintosh") || req.Header.Get("x-apollo-operation-name") != "getOrder" {
+		t.Errorf("Expected desktop Chrome getOrder headers, got %v", req.Header)
+	}
+
+	safari := MobileSafari()
+	client, _ = NewWalmartClient(ClientConfig{
+		CookieDir:       t.TempDir(),
+		HeaderProfile:   &safari,
+		HeaderOverrides: map[string]string{"accept-language": "es-US", "dnt": "", "x-trace": "abc"},
+	})
+	req, _ = http.NewRequest("GET", "https://www.walmart.com/", nil)
+	client.setPurchaseHistoryHeaders(req)
+	if !strings.Contains(req.Header.Get("user-agent"), "iPhone") {
+		t.Errorf("Expected the Safari profile, got %q", req.Header.Get("user-agent"))
+	}
+	if req.Header.Get("accept-language") != "es-US" || req.Header.Get("x-trace") != "abc" {
+		t.Errorf("Expected overrides to apply, got %v", req.Header)
+	}
+	if _, ok := req.Header["Dnt"]; ok {
+		t.Error("Expected an empty override to remove the header")
+	}
+	if req.Header.Get("x-o-gql-query") != "query PurchaseHistoryV2" {
+		t.Errorf("Unexpected operation headers: %v", req.Header)
+	}
+
+	client.SetHeaderProfile(MobileChrome())
+	req, _ = http.NewRequest("GET", "https://www.walmart.com/", nil)
+	client.setHeaders(req)
+	if req.Header.Get("sec-ch-ua-mobile") != "?1" || client.HeaderProfile().Name != "mobile-chrome" {
+		t.Errorf("Expected the switched profile, got %v", req.Header)
+	}
+}
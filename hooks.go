@@ -0,0 +1,61 @@
+package walmart
+
+import (
+	"net/http"
+	"time"
+)
+
+// OperationWarmUp names page loads made by PacingProfile in hook calls
+const OperationWarmUp = "warmUp"
+
+// Hooks observe or adjust every API request and warm-up page load the client
+// makes, e.g. for audit logs, metrics or extra headers. Any field may be nil.
+type Hooks struct {
+	// OnRequest runs just before a request is sent and may change its headers
+	OnRequest func(operation string, req *http.Request)
+
+	// OnResponse runs when a response arrives, before its body is read.
+	// resp.Request is the request that was sent.
+	OnResponse func(operation string, resp *http.Response, elapsed time.Duration)
+
+	// OnError runs for every failed attempt, whether the request could not
+	// be sent or Walmart rejected it
+	OnError func(operation string, err error)
+}
+
+// do sends req through the configured hooks
+func (c *WalmartClient) do(operation string, req *http.Request) (*http.Response, error) {
+	for _, h := range c.hooks {
+		if h.OnRequest != nil {
+			h.OnRequest(operation, req)
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		c.logger.Debug("walmart request failed", "operation", operation, "error", err)
+		c.metrics.ObserveRequest(operation, 0, elapsed)
+		return nil, err
+	}
+
+	c.metrics.ObserveRequest(operation, resp.StatusCode, elapsed)
+	c.metrics.SetSessionAge(c.CookieStore.SessionAge(time.Now()))
+	c.logger.Debug("walmart request", "operation", operation, "status", resp.StatusCode, "elapsed", elapsed)
+	for _, h := range c.hooks {
+		if h.OnResponse != nil {
+			h.OnResponse(operation, resp, elapsed)
+		}
+	}
+	return resp, nil
+}
+
+// reportError passes a failed attempt to the OnError hooks
+func (c *WalmartClient) reportError(operation string, err error) {
+	for _, h := range c.hooks {
+		if h.OnError != nil {
+			h.OnError(operation, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package walmart
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestHooks(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderId": "TEST123"}, `{"data":{"order":{"id":"TEST123"}}}`)
+	srv.Enqueue(server.RateLimited(""))
+
+	var audit []string
+	var failures []error
+	client.hooks = []Hooks{
+		{
+			OnRequest: func(operation string, req *http.Request) {
+				req.Header.Set("x-audit", "sync")
+				audit = append(audit, "request "+operation)
+			},
+		},
+		{
+			OnResponse: func(operation string, resp *http.Response, elapsed time.Duration) {
+				if resp.Request == nil || elapsed <= 0 {
+					t.Errorf("Expected the sent request and a duration, got %v %s", resp.Request, elapsed)
+				}
+				audit = append(audit, "response "+resp.Status)
+			},
+			OnError: func(operation string, err error) {
+				failures = append(failures, err)
+			},
+		},
+	}
+
+	if _, err := client.GetOrder("TEST123", true); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected rate limited, got %v", err)
+	}
+	if _, err := client.GetOrder("TEST123", true); err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+
+	want := []string{"request getOrder", "response 429 Too Many Requests", "request getOrder", "response 200 OK"}
+	if len(audit) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, audit)
+	}
+	for i := range want {
+		if audit[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], audit[i])
+		}
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], ErrRateLimited) {
+		t.Errorf("Expected one reported failure, got %v", failures)
+	}
+	for _, req := range srv.Requests() {
+		if req.Header.Get("x-audit") != "sync" {
+			t.Error("OnRequest header changes should be sent")
+		}
+	}
+}
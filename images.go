@@ -0,0 +1,175 @@
+package walmart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+)
+
+// imageIndexFile maps source URLs to cached filenames inside the media dir
+const imageIndexFile = "index.json"
+
+// maxImageSize guards against unexpectedly large downloads
+const maxImageSize = 20 << 20
+
+// ImageDownloadOptions controls DownloadItemImages
+type ImageDownloadOptions struct {
+	FullSize bool // Fetch the original image instead of the thumbnail variant
+	Width    int  // Resize to this width (with Height) when not FullSize
+	Height   int  // Resize to this height (with Width) when not FullSize
+}
+
+// DownloadItemImages saves product images for every item in the order into
+// dir, named by content hash so identical images are stored once. Images
+// already in the cache are not fetched again. The result maps
+// ItemKey(group ID, item ID) to the local file path.
+func (c *WalmartClient) DownloadItemImages(order *Order, dir string, opts ImageDownloadOptions) (map[string]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create image dir: %w", err)
+	}
+
+	index := make(map[string]string)
+	indexPath := filepath.Join(dir, imageIndexFile)
+	if data, err := os.ReadFile(indexPath); err == nil {
+		_ = json.Unmarshal(data, &index)
+	}
+
+	paths := make(map[string]string)
+	for _, group := range order.Groups {
+		for _, item := range group.Items {
+			if item.ProductInfo == nil || item.ProductInfo.ImageInfo.ThumbnailURL == "" {
+				continue
+			}
+			key := ItemKey(group.ID, item.ID)
+
+			imageURL := item.ProductInfo.ImageInfo.ThumbnailURL
+			switch {
+			case opts.FullSize:
+				imageURL = item.ProductInfo.ImageInfo.FullSizeURL()
+			case opts.Width > 0 && opts.Height > 0:
+				imageURL = item.ProductInfo.ImageInfo.SizedURL(opts.Width, opts.Height)
+			}
+
+			if name, ok := index[imageURL]; ok {
+				if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+					paths[key] = filepath.Join(dir, name)
+					continue
+				}
+			}
+
+			name, err := c.downloadImage(imageURL, dir)
+			if err != nil {
+				return paths, fmt.Errorf("failed to download image for item %s: %w", key, err)
+			}
+			index[imageURL] = name
+			paths[key] = filepath.Join(dir, name)
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return paths, err
+	}
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return paths, fmt.Errorf("failed to save image index: %w", err)
+	}
+
+	return paths, nil
+}
+
+// ItemKey identifies an item within an order; item IDs are only unique
+// within their group
+func ItemKey(groupID, itemID string) string {
+	return groupID + "/" + itemID
+}
+
+// downloadImage fetches one image and stores it under its content hash
+func (c *WalmartClient) downloadImage(imageURL, dir string) (string, error) {
+	// The API client stops at redirects; image CDNs rely on them
+	client := *c.httpClient
+	client.CheckRedirect = nil
+
+	resp, err := client.Get(imageURL)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxImageSize {
+		return "", fmt.Errorf("image larger than %d bytes", maxImageSize)
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:16]) + imageExtension(imageURL, resp.Header.Get("Content-Type"))
+
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save image: %w", err)
+	}
+	return name, nil
+}
+
+func imageExtension(imageURL, contentType string) string {
+	if u, err := url.Parse(imageURL); err == nil {
+		if ext := path.Ext(u.Path); ext != "" {
+			return ext
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			return exts[0]
+		}
+	}
+	return ""
+}
+
+// imageSizeParams are the query parameters the Walmart image CDN resizes with
+var imageSizeParams = []string{"odnHeight", "odnWidth", "odnBg"}
+
+// SizedURL returns the image resized by the CDN to fit width x height
+func (i ImageInfo) SizedURL(width, height int) string {
+	u, err := url.Parse(i.ThumbnailURL)
+	if err != nil || i.ThumbnailURL == "" {
+		return i.ThumbnailURL
+	}
+
+	query := u.Query()
+	query.Set("odnHeight", strconv.Itoa(height))
+	query.Set("odnWidth", strconv.Itoa(width))
+	if query.Get("odnBg") == "" {
+		query.Set("odnBg", "FFFFFF")
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// FullSizeURL returns the original full-resolution image
+func (i ImageInfo) FullSizeURL() string {
+	u, err := url.Parse(i.ThumbnailURL)
+	if err != nil || i.ThumbnailURL == "" {
+		return i.ThumbnailURL
+	}
+
+	query := u.Query()
+	for _, param := range imageSizeParams {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,122 @@
+package walmart
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDownloadItemImages(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("jpeg-bytes-" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	order := &Order{
+		Groups: []OrderGroup{{
+			Items: []OrderItem{
+				{ID: "1", ProductInfo: &ProductInfo{ImageInfo: ImageInfo{ThumbnailURL: server.URL + "/a.jpeg?odnHeight=100&odnWidth=100"}}},
+				{ID: "2", ProductInfo: &ProductInfo{ImageInfo: ImageInfo{ThumbnailURL: server.URL + "/b.jpeg?odnHeight=100&odnWidth=100"}}},
+				{ID: "3", ProductInfo: &ProductInfo{}},
+			},
+		}},
+	}
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	client.httpClient = server.Client()
+	dir := t.TempDir()
+
+	paths, err := client.DownloadItemImages(order, dir, ImageDownloadOptions{})
+	if err != nil {
+		t.Fatalf("Failed to download images: %v", err)
+	}
+
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 images, got %d", len(paths))
+	}
+	if filepath.Ext(paths[ItemKey("", "1")]) != ".jpeg" {
+		t.Errorf("Expected .jpeg extension, got %s", paths[ItemKey("", "1")])
+	}
+	if data, _ := os.ReadFile(paths[ItemKey("", "2")]); string(data) != "jpeg-bytes-/b.jpeg" {
+		t.Errorf("Unexpected image content: %q", data)
+	}
+
+	// Second run is served from the cache
+	if _, err := client.DownloadItemImages(order, dir, ImageDownloadOptions{}); err != nil {
+		t.Fatalf("Failed on cached run: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected cached images to be reused, got %d requests", requests)
+	}
+}
+
+func TestDownloadItemImagesAcrossGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved.jpeg" {
+			http.Redirect(w, r, "/b.jpeg", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("jpeg-bytes-" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	order := &Order{
+		Groups: []OrderGroup{
+			{ID: "g1", Items: []OrderItem{{ID: "0", ProductInfo: &ProductInfo{ImageInfo: ImageInfo{ThumbnailURL: server.URL + "/a.jpeg"}}}}},
+			{ID: "g2", Items: []OrderItem{{ID: "0", ProductInfo: &ProductInfo{ImageInfo: ImageInfo{ThumbnailURL: server.URL + "/moved.jpeg"}}}}},
+		},
+	}
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	paths, err := client.DownloadItemImages(order, t.TempDir(), ImageDownloadOptions{})
+	if err != nil {
+		t.Fatalf("Failed to download images: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected an image per group, got %v", paths)
+	}
+	if data, _ := os.ReadFile(paths[ItemKey("g2", "0")]); string(data) != "jpeg-bytes-/b.jpeg" {
+		t.Errorf("Expected the redirect to be followed, got %q", data)
+	}
+}
+
+func TestDownloadItemImagesTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, maxImageSize+1))
+	}))
+	defer server.Close()
+
+	order := &Order{Groups: []OrderGroup{{Items: []OrderItem{
+		{ID: "1", ProductInfo: &ProductInfo{ImageInfo: ImageInfo{ThumbnailURL: server.URL + "/huge.jpeg"}}},
+	}}}}
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	dir := t.TempDir()
+	if _, err := client.DownloadItemImages(order, dir, ImageDownloadOptions{}); err == nil {
+		t.Fatal("Expected oversized images to fail rather than be truncated")
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.jpeg")); len(files) != 0 {
+		t.Errorf("Expected nothing saved, got %v", files)
+	}
+}
+
+func TestImageInfoVariants(t *testing.T) {
+	info := ImageInfo{ThumbnailURL: "https://i5.walmartimages.com/asr/abc.jpeg?odnHeight=180&odnWidth=180&odnBg=FFFFFF"}
+
+	if got := info.FullSizeURL(); got != "https://i5.walmartimages.com/asr/abc.jpeg" {
+		t.Errorf("Unexpected full-size URL: %s", got)
+	}
+
+	if got := info.SizedURL(640, 480); got != "https://i5.walmartimages.com/asr/abc.jpeg?odnBg=FFFFFF&odnHeight=480&odnWidth=640" {
+		t.Errorf("Unexpected sized URL: %s", got)
+	}
+
+	if got := (ImageInfo{}).SizedURL(100, 100); got != "" {
+		t.Errorf("Empty image should stay empty, got %s", got)
+	}
+}
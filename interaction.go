@@ -0,0 +1,33 @@
+package walmart
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Remediation codes tell automation how to resolve an InteractionRequiredError
+const (
+	RemediationImportCurl    = "import_curl"    // Call InitializeFromCurl with a fresh browser capture
+	RemediationCookieRelay   = "cookie_relay"   // Serve the cookie relay page and paste from another device
+	RemediationExtensionPush = "extension_push" // Wait for the browser extension to push cookies
+)
+
+// ErrInteractionRequired matches any InteractionRequiredError via errors.Is
+var ErrInteractionRequired = errors.New("user interaction required")
+
+// InteractionRequiredError is returned instead of prompting on stdin when
+// the client is NonInteractive
+type InteractionRequiredError struct {
+	Action      string   `json:"action"`      // Flow that needed a human, e.g. "refresh_cookies"
+	Remediation string   `json:"remediation"` // One of the Remediation* codes
+	Steps       []string `json:"steps"`       // Human-readable instructions
+}
+
+func (e *InteractionRequiredError) Error() string {
+	return fmt.Sprintf("%s requires user interaction (remediation: %s)", e.Action, e.Remediation)
+}
+
+// Is makes errors.Is(err, ErrInteractionRequired) work
+func (e *InteractionRequiredError) Is(target error) bool {
+	return target == ErrInteractionRequired
+}
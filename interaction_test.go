@@ -0,0 +1,30 @@
+package walmart
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNonInteractiveRefresh(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), NonInteractive: true})
+
+	err := client.RefreshFromBrowser()
+	if !errors.Is(err, ErrInteractionRequired) {
+		t.Fatalf("Expected ErrInteractionRequired, got %v", err)
+	}
+
+	var interaction *InteractionRequiredError
+	if !errors.As(err, &interaction) {
+		t.Fatal("Expected an InteractionRequiredError")
+	}
+	if interaction.Remediation != RemediationImportCurl || len(interaction.Steps) == 0 {
+		t.Errorf("Unexpected remediation: %+v", interaction)
+	}
+
+	// The prompt strategy surfaces the same error through the auth chain
+	manager := NewAuthManager(client, PromptStrategy())
+	err = manager.Run(func() error { return &APIError{StatusCode: 403, Kind: ErrSessionExpired} })
+	if !errors.Is(err, ErrAuthExhausted) {
+		t.Errorf("Expected ErrAuthExhausted, got %v", err)
+	}
+}
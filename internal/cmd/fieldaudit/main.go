@@ -0,0 +1,249 @@
+// Command fieldaudit compares captured Walmart responses against the model
+// structs and writes a field reference, flagging JSON fields the models do
+// not capture yet along with a suggested Go declaration for review.
+//
+// Usage:
+//
+//	go run ./internal/cmd/fieldaudit -corpus testdata/payloads -out docs/fields.md [-check]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// field is one JSON path seen in the corpus
+type field struct {
+	Path     string
+	JSONType string
+	GoType   string // Empty when the models do not capture the field
+	Files    map[string]bool
+}
+
+func main() {
+	corpus := flag.String("corpus", "testdata/payloads", "directory of captured JSON responses")
+	out := flag.String("out", "docs/fields.md", "markdown report to write")
+	check := flag.Bool("check", false, "exit non-zero when unmodeled fields are found")
+	flag.Parse()
+
+	fields, err := auditDir(*corpus)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	unmodeled := writeReport(f, fields)
+	fmt.Printf("fieldaudit: %d fields, %d not modeled, report written to %s\n", len(fields), unmodeled, *out)
+
+	if *check && unmodeled > 0 {
+		os.Exit(1)
+	}
+}
+
+// auditDir walks every .json file in dir against the matching root model
+func auditDir(dir string) ([]*field, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .json payloads in %s", dir)
+	}
+
+	fields := make(map[string]*field)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := auditPayload(filepath.Base(path), data, fields); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	result := make([]*field, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// auditPayload detects the operation from the payload shape and walks it
+func auditPayload(name string, data []byte, fields map[string]*field) error {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	root := reflect.TypeOf(walmart.OrderResponse{})
+	if body, ok := payload["data"].(map[string]interface{}); ok {
+		if _, ok := body["orderHistoryV2"]; ok {
+			root = reflect.TypeOf(walmart.PurchaseHistoryResponse{})
+		}
+	}
+
+	walk(root.Name(), payload, root, name, fields)
+	return nil
+}
+
+func walk(path string, value interface{}, t reflect.Type, file string, fields map[string]*field) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t == nil || t.Kind() != reflect.Struct {
+			return
+		}
+		byTag := jsonFields(t)
+		for key, child := range v {
+			childPath := path + "." + key
+			goField, modeled := byTag[key]
+
+			f := record(childPath, child, file, fields)
+			if !modeled {
+				continue
+			}
+			f.GoType = typeName(goField.Type)
+			walk(childPath, child, goField.Type, file, fields)
+		}
+	case []interface{}:
+		if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+			return
+		}
+		for _, child := range v {
+			walk(path+"[]", child, t.Elem(), file, fields)
+		}
+	}
+}
+
+func record(path string, value interface{}, file string, fields map[string]*field) *field {
+	f, ok := fields[path]
+	if !ok {
+		f = &field{Path: path, Files: make(map[string]bool)}
+		fields[path] = f
+	}
+	// Prefer a concrete type over null when samples disagree
+	if jsonType := jsonTypeOf(value); f.JSONType == "" || f.JSONType == "null" {
+		f.JSONType = jsonType
+	}
+	f.Files[file] = true
+	return f
+}
+
+// typeName shortens anonymous struct types, which print as their full definition
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Struct && t.Name() == "" {
+		return "struct"
+	}
+	return t.String()
+}
+
+// jsonFields maps JSON names to struct fields, following the encoding/json rules
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = sf.Name
+		}
+		fields[tag] = sf
+	}
+	return fields
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// suggestDecl proposes a struct field for an unmodeled JSON key
+func suggestDecl(f *field) string {
+	key := f.Path[strings.LastIndex(f.Path, ".")+1:]
+	name := strings.ToUpper(key[:1]) + key[1:]
+
+	goType := map[string]string{
+		"string": "string",
+		"number": "float64",
+		"bool":   "bool",
+		"array":  "[]json.RawMessage",
+		"object": "json.RawMessage",
+		"null":   "*json.RawMessage",
+	}[f.JSONType]
+
+	return fmt.Sprintf("%s %s `json:\"%s\"`", name, goType, key)
+}
+
+// writeReport renders the markdown reference and returns the unmodeled count
+func writeReport(w io.Writer, fields []*field) int {
+	fmt.Fprintln(w, "# Response Field Reference")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Code generated by fieldaudit from testdata/payloads. DO NOT EDIT.")
+	fmt.Fprintln(w)
+
+	var unmodeled []*field
+	for _, f := range fields {
+		if f.GoType == "" {
+			unmodeled = append(unmodeled, f)
+		}
+	}
+
+	if len(unmodeled) > 0 {
+		fmt.Fprintln(w, "## Not Modeled")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Path | JSON type | Seen in | Suggested field |")
+		fmt.Fprintln(w, "|------|-----------|---------|-----------------|")
+		for _, f := range unmodeled {
+			fmt.Fprintf(w, "| `%s` | %s | %d | `%s` |\n", f.Path, f.JSONType, len(f.Files), suggestDecl(f))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "## Modeled")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Path | JSON type | Go type | Seen in |")
+	fmt.Fprintln(w, "|------|-----------|---------|---------|")
+	for _, f := range fields {
+		if f.GoType != "" {
+			fmt.Fprintf(w, "| `%s` | %s | `%s` | %d |\n", f.Path, f.JSONType, f.GoType, len(f.Files))
+		}
+	}
+
+	return len(unmodeled)
+}
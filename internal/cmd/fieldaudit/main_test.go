@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAuditPayloadFlagsUnmodeledFields(t *testing.T) {
+	payload := `{"data":{"order":{"id":"1","giftMessage":"Happy birthday",
+		"groups_2101":[{"items":[{"quantity":1,"aisleLocation":{"aisle":"A12"}}]}]}}}`
+
+	fields := make(map[string]*field)
+	if err := auditPayload("order.json", []byte(payload), fields); err != nil {
+		t.Fatalf("audit failed: %v", err)
+	}
+
+	if f := fields["OrderResponse.data.order.id"]; f == nil || f.GoType != "string" {
+		t.Errorf("Expected id to be modeled as string, got %+v", f)
+	}
+	if f := fields["OrderResponse.data.order.groups_2101[].items[].quantity"]; f == nil || f.GoType != "float64" {
+		t.Errorf("Expected quantity to be modeled as float64, got %+v", f)
+	}
+
+	gift := fields["OrderResponse.data.order.giftMessage"]
+	if gift == nil || gift.GoType != "" {
+		t.Fatalf("Expected giftMessage to be flagged, got %+v", gift)
+	}
+	if got := suggestDecl(gift); got != "GiftMessage string `json:\"giftMessage\"`" {
+		t.Errorf("Unexpected suggestion: %s", got)
+	}
+
+	if _, ok := fields["OrderResponse.data.order.groups_2101[].items[].aisleLocation.aisle"]; ok {
+		t.Error("Children of an unmodeled field should not be listed separately")
+	}
+
+	list := make([]*field, 0, len(fields))
+	for _, f := range fields {
+		list = append(list, f)
+	}
+	var out bytes.Buffer
+	if n := writeReport(&out, list); n != 2 {
+		t.Errorf("Expected 2 unmodeled fields, got %d", n)
+	}
+	if !strings.Contains(out.String(), "## Not Modeled") {
+		t.Errorf("Report missing unmodeled section:\n%s", out.String())
+	}
+}
+
+func TestAuditCorpus(t *testing.T) {
+	fields, err := auditDir("../../../testdata/payloads")
+	if err != nil {
+		t.Fatalf("Failed to audit corpus: %v", err)
+	}
+	if len(fields) == 0 {
+		t.Error("Expected fields from the checked-in corpus")
+	}
+}
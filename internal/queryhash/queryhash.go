@@ -0,0 +1,10 @@
+// Package queryhash holds the persisted-query hashes the client ships with.
+// It is shared by the client and the fake server in walmarttest/server,
+// which can't import the client because the client's tests import it.
+package queryhash
+
+// Persisted-query hashes the web app used when this version was released
+const (
+	GetOrder        = "d0622497daef19150438d07c506739d451cad6749cf45c3b4db95f2f5a0a65c4"
+	PurchaseHistory = "2c3d5a832b56671dca1ed0ec84940f274d0bc80821db4ad7481e496c0ad5847e"
+)
@@ -0,0 +1,42 @@
+package walmart
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestLoggerReceivesProgress(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	var buf bytes.Buffer
+	client.logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	srv.On(server.PurchaseHistoryHash, map[string]interface{}{"input": map[string]interface{}{"cursor": ""}},
+		`{"data":{"orderHistoryV2":{"pageInfo":{"nextPageCursor":"p2"},"orderGroups":[{"orderId":"1"}]}}}`)
+	srv.On(server.PurchaseHistoryHash, map[string]interface{}{"input": map[string]interface{}{"cursor": "p2"}},
+		`{"data":{"orderHistoryV2":{"orderGroups":[{"orderId":"2"}]}}}`)
+
+	orders, err := client.GetAllOrders(5)
+	if err != nil || len(orders) != 2 {
+		t.Fatalf("Expected 2 orders, got %d (%v)", len(orders), err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "fetched purchase history page") || !strings.Contains(out, "page=1") {
+		t.Errorf("Expected page progress in the log, got:\n%s", out)
+	}
+	if strings.Count(out, "walmart request") != 2 {
+		t.Errorf("Expected a debug line per request, got:\n%s", out)
+	}
+}
+
+func TestDefaultLoggerDiscards(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	if client.logger.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected the default logger to discard everything")
+	}
+}
@@ -0,0 +1,102 @@
+package walmart
+
+import (
+	"expvar"
+	"strconv"
+	"time"
+)
+
+// Metrics receives counters and timings from the client, e.g. to export to
+// Prometheus. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest records one request; status is 0 if it was never answered
+	ObserveRequest(operation string, status int, elapsed time.Duration)
+	// IncRetry counts a retry of operation
+	IncRetry(operation string)
+	// ObserveRateLimitWait records time spent waiting on the rate limiter
+	ObserveRateLimitWait(operation string, wait time.Duration)
+	// SetSessionAge reports the age of the oldest essential cookie
+	SetSessionAge(age time.Duration)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, int, time.Duration)  {}
+func (noopMetrics) IncRetry(string)                            {}
+func (noopMetrics) ObserveRateLimitWait(string, time.Duration) {}
+func (noopMetrics) SetSessionAge(time.Duration)                {}
+
+// ExpvarMetrics publishes counters under /debug/vars:
+// requests.<operation>.<status>, retries.<operation>,
+// rate_limit_wait_seconds.<operation>, request_seconds.<operation> and
+// session_age_seconds
+type ExpvarMetrics struct {
+	vars       *expvar.Map
+	requests   *expvar.Map
+	latency    *expvar.Map
+	retries    *expvar.Map
+	waits      *expvar.Map
+	sessionAge *expvar.Float
+}
+
+// NewExpvarMetrics publishes metrics under name; like expvar.Publish, it
+// panics if name is already taken
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		vars:       expvar.NewMap(name),
+		requests:   new(expvar.Map).Init(),
+		latency:    new(expvar.Map).Init(),
+		retries:    new(expvar.Map).Init(),
+		waits:      new(expvar.Map).Init(),
+		sessionAge: new(expvar.Float),
+	}
+	m.vars.Set("requests", m.requests)
+	m.vars.Set("request_seconds", m.latency)
+	m.vars.Set("retries", m.retries)
+	m.vars.Set("rate_limit_wait_seconds", m.waits)
+	m.vars.Set("session_age_seconds", m.sessionAge)
+	return m
+}
+
+// ObserveRequest implements Metrics
+func (m *ExpvarMetrics) ObserveRequest(operation string, status int, elapsed time.Duration) {
+	m.requests.Add(operation+"."+strconv.Itoa(status), 1)
+	m.latency.AddFloat(operation, elapsed.Seconds())
+}
+
+// IncRetry implements Metrics
+func (m *ExpvarMetrics) IncRetry(operation string) {
+	m.retries.Add(operation, 1)
+}
+
+// ObserveRateLimitWait implements Metrics
+func (m *ExpvarMetrics) ObserveRateLimitWait(operation string, wait time.Duration) {
+	m.waits.AddFloat(operation, wait.Seconds())
+}
+
+// SetSessionAge implements Metrics
+func (m *ExpvarMetrics) SetSessionAge(age time.Duration) {
+	m.sessionAge.Set(age.Seconds())
+}
+
+// Vars returns the published map, e.g. for tests or custom exporters
+func (m *ExpvarMetrics) Vars() *expvar.Map {
+	return m.vars
+}
+
+// essentialCookies are the cookies a session cannot work without
+var essentialCookies = []string{"CID", "SPID", "auth", "customer"}
+
+// SessionAge is the age of the oldest essential cookie present, or 0 when
+// there are none
+func (cs *CookieStore) SessionAge(now time.Time) time.Duration {
+	var age time.Duration
+	for _, name := range essentialCookies {
+		if cookie := cs.Get(name); cookie != nil && !cookie.LastUpdate.IsZero() {
+			if a := now.Sub(cookie.LastUpdate); a > age {
+				age = a
+			}
+		}
+	}
+	return age
+}
@@ -0,0 +1,55 @@
+package walmart
+
+import (
+	"expvar"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	metrics := NewExpvarMetrics("walmart_test_metrics")
+	client.metrics = metrics
+	client.retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	client.CookieStore.Set("CID", &Cookie{Value: "test", LastUpdate: time.Now().Add(-2 * time.Hour)})
+
+	srv.On(server.GetOrderHash, nil, `{"data":{"order":{"id":"TEST123"}}}`)
+	srv.Enqueue(server.Response{Status: http.StatusBadGateway})
+
+	if _, err := client.GetOrder("TEST123", true); err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+
+	vars := metrics.Vars()
+	requests := vars.Get("requests").(*expvar.Map)
+	if got := requests.Get("getOrder.502"); got == nil || got.String() != "1" {
+		t.Errorf("Expected one 502, got %v", got)
+	}
+	if got := requests.Get("getOrder.200"); got == nil || got.String() != "1" {
+		t.Errorf("Expected one 200, got %v", got)
+	}
+	if got := vars.Get("retries").(*expvar.Map).Get("getOrder"); got == nil || got.String() != "1" {
+		t.Errorf("Expected one retry, got %v", got)
+	}
+	if age := vars.Get("session_age_seconds").(*expvar.Float).Value(); age < 7200 {
+		t.Errorf("Expected the stale CID to drive session age, got %.0fs", age)
+	}
+}
+
+func TestSessionAge(t *testing.T) {
+	now := time.Date(2025, 9, 5, 12, 0, 0, 0, time.UTC)
+	store := &CookieStore{Cookies: make(map[string]*Cookie)}
+	if age := store.SessionAge(now); age != 0 {
+		t.Errorf("Expected 0 without cookies, got %s", age)
+	}
+
+	store.Set("CID", &Cookie{Value: "a", LastUpdate: now.Add(-time.Hour)})
+	store.Set("SPID", &Cookie{Value: "b", LastUpdate: now.Add(-3 * time.Hour)})
+	store.Set("bstc", &Cookie{Value: "c", LastUpdate: now.Add(-48 * time.Hour)})
+	if age := store.SessionAge(now); age != 3*time.Hour {
+		t.Errorf("Expected the oldest essential cookie to count, got %s", age)
+	}
+}
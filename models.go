@@ -1,6 +1,11 @@
 package walmart
 
-import "fmt"
+//go:generate go run ./internal/cmd/fieldaudit -corpus testdata/payloads -out docs/fields.md
+
+import (
+	"fmt"
+	"time"
+)
 
 // OrderResponse is the top-level GraphQL response
 type OrderResponse struct {
@@ -49,6 +54,22 @@ type OrderPaymentMethod struct {
 	PaymentType string `json:"paymentType"`
 }
 
+// orderDateLayouts are the timestamp formats seen in orderDate
+var orderDateLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+}
+
+// OrderTime parses OrderDate
+func (o *Order) OrderTime() (time.Time, error) {
+	for _, layout := range orderDateLayouts {
+		if t, err := time.Parse(layout, o.OrderDate); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized order date %q", o.OrderDate)
+}
+
 // GetItems extracts all items from all groups
 func (o *Order) GetItems() []OrderItem {
 	var items []OrderItem
@@ -189,8 +210,8 @@ func (o *Order) CalculateOrderTotal() float64 {
 	total := 0.0
 	for _, group := range o.Groups {
 		for _, item := range group.Items {
-			if item.PriceInfo != nil && item.PriceInfo.LinePrice != nil {
-				total += item.PriceInfo.LinePrice.Value
+			if price, ok := item.LinePrice(); ok {
+				total += price
 			}
 		}
 	}
@@ -206,6 +227,33 @@ func (o *Order) GetItemCount() int {
 	return count
 }
 
+// MissingItems is a heuristic estimate of how many items the group's
+// itemCount promises but the payload left out. itemCount may count units rather than lines, so a
+// group only counts as short when it exceeds both.
+func (g *OrderGroup) MissingItems() int {
+	units := 0.0
+	for _, item := range g.Items {
+		units += item.Quantity
+	}
+	missing := g.ItemCount - len(g.Items)
+	if missing <= 0 || float64(g.ItemCount) <= units {
+		return 0
+	}
+	return missing
+}
+
+// ItemsTruncated reports whether Walmart appears to have returned fewer items
+// than the order holds. It is detection only: nothing fetches the missing
+// items, so totals computed from GetItems will be short for such orders.
+func (o *Order) ItemsTruncated() bool {
+	for i := range o.Groups {
+		if o.Groups[i].MissingItems() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // CalculateTotalWithTip calculates and sets the TotalWithTip field
 func (o *Order) CalculateTotalWithTip() {
 	if o.PriceDetails == nil || o.PriceDetails.GrandTotal == nil {
@@ -229,6 +277,91 @@ func (o *Order) CalculateTotalWithTip() {
 	}
 }
 
+// Nil-safe accessors
+
+func (p *PriceLineItem) value() (float64, bool) {
+	if p == nil {
+		return 0, false
+	}
+	return p.Value, true
+}
+
+// GrandTotalValue returns the order grand total if present
+func (o *Order) GrandTotalValue() (float64, bool) {
+	if o == nil || o.PriceDetails == nil {
+		return 0, false
+	}
+	return o.PriceDetails.GrandTotal.value()
+}
+
+// SubTotalValue returns the order subtotal if present
+func (o *Order) SubTotalValue() (float64, bool) {
+	if o == nil || o.PriceDetails == nil {
+		return 0, false
+	}
+	return o.PriceDetails.SubTotal.value()
+}
+
+// TaxTotalValue returns the order tax if present
+func (o *Order) TaxTotalValue() (float64, bool) {
+	if o == nil || o.PriceDetails == nil {
+		return 0, false
+	}
+	return o.PriceDetails.TaxTotal.value()
+}
+
+// DriverTipValue returns the driver tip if present
+func (o *Order) DriverTipValue() (float64, bool) {
+	if o == nil || o.PriceDetails == nil {
+		return 0, false
+	}
+	return o.PriceDetails.DriverTip.value()
+}
+
+// ChargedTotal returns the amount actually charged: the total with tip
+// when known, otherwise the grand total
+func (o *Order) ChargedTotal() (float64, bool) {
+	if o == nil || o.PriceDetails == nil {
+		return 0, false
+	}
+	if total, ok := o.PriceDetails.TotalWithTip.value(); ok {
+		return total, true
+	}
+	return o.PriceDetails.GrandTotal.value()
+}
+
+// Name returns the product name, or "" when product info is missing
+func (i OrderItem) Name() string {
+	if i.ProductInfo == nil {
+		return ""
+	}
+	return i.ProductInfo.Name
+}
+
+// USItemID returns the Walmart item number, or "" when product info is missing
+func (i OrderItem) USItemID() string {
+	if i.ProductInfo == nil {
+		return ""
+	}
+	return i.ProductInfo.USItemID
+}
+
+// LinePrice returns the price paid for the line if present
+func (i OrderItem) LinePrice() (float64, bool) {
+	if i.PriceInfo == nil || i.PriceInfo.LinePrice == nil {
+		return 0, false
+	}
+	return i.PriceInfo.LinePrice.Value, true
+}
+
+// UnitPrice returns the per-unit price if present
+func (i OrderItem) UnitPrice() (float64, bool) {
+	if i.PriceInfo == nil || i.PriceInfo.UnitPrice == nil {
+		return 0, false
+	}
+	return i.PriceInfo.UnitPrice.Value, true
+}
+
 // IsDeliveryOrder checks if the order is a delivery order
 func (o *Order) IsDeliveryOrder() bool {
 	for _, group := range o.Groups {
@@ -0,0 +1,146 @@
+package walmart
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var orderSeeds = []string{
+	`{"data":{"order":null}}`,
+	`{"data":{"order":{"groups_2101":null,"priceDetails":null}}}`,
+	`{"data":{"order":{"groups_2101":[null,{"items":null}],"priceDetails":{"grandTotal":null,"driverTip":{"value":3}}}}}`,
+	`{"data":{"order":{"id":"1","orderDate":"2024-01-01T12:00:00.000-0700","groups_2101":[{"fulfillmentType":"SC_DELIVERY",
+		"items":[null,{"quantity":1.5,"productInfo":null,"priceInfo":{"linePrice":null}}]}],
+		"priceDetails":{"grandTotal":{"value":10},"driverTip":{"value":2},"fees":[null]}}}}`,
+}
+
+// exerciseOrder calls every helper that reads nested pointers
+func exerciseOrder(order *Order) {
+	order.GetItems()
+	order.GetItemCount()
+	order.CalculateOrderTotal()
+	order.IsDeliveryOrder()
+	order.CalculateTotalWithTip()
+	_, _ = order.OrderTime()
+	_ = order.ContentHash()
+	_ = DiffOrders(order, order).String()
+	_ = DiffOrders(nil, order).String()
+	for _, item := range order.GetItems() {
+		_ = item.ContentHash()
+	}
+}
+
+func FuzzOrderResponse(f *testing.F) {
+	for _, seed := range orderSeeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var resp OrderResponse
+		if err := json.Unmarshal(data, &resp); err != nil || resp.Data.Order == nil {
+			return
+		}
+		exerciseOrder(resp.Data.Order)
+	})
+}
+
+func FuzzPurchaseHistoryResponse(f *testing.F) {
+	f.Add([]byte(`{"data":{"orderHistoryV2":{"orderGroups":null}}}`))
+	f.Add([]byte(`{"data":{"orderHistoryV2":{"orderGroups":[null,{"store":null,"status":null,"items":[null]}]}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var resp PurchaseHistoryResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return
+		}
+		for _, summary := range resp.Data.OrderHistoryV2.OrderGroups {
+			_ = summary.ContentHash()
+		}
+		_ = ExcludeClassified(resp.Data.OrderHistoryV2.OrderGroups, "spark")
+	})
+}
+
+func TestSparseOrdersDoNotPanic(t *testing.T) {
+	for _, seed := range orderSeeds {
+		var resp OrderResponse
+		if err := json.Unmarshal([]byte(seed), &resp); err != nil {
+			t.Fatalf("Seed failed to parse: %v", err)
+		}
+		if resp.Data.Order != nil {
+			exerciseOrder(resp.Data.Order)
+		}
+	}
+}
+
+func TestNilSafeAccessors(t *testing.T) {
+	var empty *Order
+	if _, ok := empty.GrandTotalValue(); ok {
+		t.Error("Nil order should have no grand total")
+	}
+
+	order := &Order{PriceDetails: &OrderPriceDetails{
+		GrandTotal: &PriceLineItem{Value: 50},
+		DriverTip:  &PriceLineItem{Value: 5},
+	}}
+	if total, ok := order.GrandTotalValue(); !ok || total != 50 {
+		t.Errorf("Expected grand total 50, got %v %v", total, ok)
+	}
+	if _, ok := order.TaxTotalValue(); ok {
+		t.Error("Missing tax should report false")
+	}
+	if total, _ := order.ChargedTotal(); total != 50 {
+		t.Errorf("Expected charged total to fall back to grand total, got %v", total)
+	}
+	order.CalculateTotalWithTip()
+	if total, _ := order.ChargedTotal(); total != 55 {
+		t.Errorf("Expected charged total with tip 55, got %v", total)
+	}
+
+	var item OrderItem
+	if item.Name() != "" || item.USItemID() != "" {
+		t.Error("Sparse item should have empty name and ID")
+	}
+	if _, ok := item.LinePrice(); ok {
+		t.Error("Sparse item should have no line price")
+	}
+
+	item = OrderItem{
+		ProductInfo: &ProductInfo{Name: "Milk", USItemID: "10450114"},
+		PriceInfo:   &ItemPrice{LinePrice: &Price{Value: 3.48}, UnitPrice: &Price{Value: 3.48}},
+	}
+	if item.Name() != "Milk" || item.USItemID() != "10450114" {
+		t.Errorf("Unexpected item accessors: %q %q", item.Name(), item.USItemID())
+	}
+	if price, ok := item.UnitPrice(); !ok || price != 3.48 {
+		t.Errorf("Unexpected unit price: %v %v", price, ok)
+	}
+}
+
+func TestItemsTruncated(t *testing.T) {
+	lines := func(n int, qty float64) []OrderItem {
+		items := make([]OrderItem, n)
+		for i := range items {
+			items[i].Quantity = qty
+		}
+		return items
+	}
+
+	tests := []struct {
+		name    string
+		group   OrderGroup
+		missing int
+	}{
+		{"complete", OrderGroup{ItemCount: 3, Items: lines(3, 1)}, 0},
+		{"count is units", OrderGroup{ItemCount: 6, Items: lines(3, 2)}, 0},
+		{"truncated", OrderGroup{ItemCount: 160, Items: lines(100, 1)}, 60},
+	}
+	for _, tt := range tests {
+		if got := tt.group.MissingItems(); got != tt.missing {
+			t.Errorf("%s: expected %d missing, got %d", tt.name, tt.missing, got)
+		}
+		order := &Order{Groups: []OrderGroup{tt.group}}
+		if order.ItemsTruncated() != (tt.missing > 0) {
+			t.Errorf("%s: unexpected ItemsTruncated", tt.name)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package walmart
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- RFC 6238 TOTP is defined over HMAC-SHA1
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OTPChallenge describes a one-time passcode request met during login
+type OTPChallenge struct {
+	Channel     string `json:"channel"`     // "sms", "email", "totp"
+	Destination string `json:"destination"` // Masked phone or email as shown by Walmart
+}
+
+// OTPProvider supplies the passcode for a login challenge
+type OTPProvider func(challenge OTPChallenge) (string, error)
+
+// ErrNoOTPProvider is returned when a login flow hits a challenge but no
+// provider is configured on the AuthManager
+var ErrNoOTPProvider = errors.New("login requires a one-time passcode but no OTP provider is configured")
+
+// RequestOTP lets login strategies obtain a passcode from the configured provider
+func (m *AuthManager) RequestOTP(challenge OTPChallenge) (string, error) {
+	if m.OTP == nil {
+		return "", ErrNoOTPProvider
+	}
+
+	code, err := m.OTP(challenge)
+	if err != nil {
+		return "", fmt.Errorf("failed to get OTP: %w", err)
+	}
+	return strings.TrimSpace(code), nil
+}
+
+// PromptOTP reads the passcode from a terminal
+func PromptOTP(in io.Reader, out io.Writer) OTPProvider {
+	reader := bufio.NewReader(in)
+	return func(challenge OTPChallenge) (string, error) {
+		if challenge.Destination != "" {
+			fmt.Fprintf(out, "Enter the code Walmart sent via %s to %s: ", challenge.Channel, challenge.Destination)
+		} else {
+			fmt.Fprint(out, "Enter your Walmart verification code: ")
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+// WebhookOTP posts the challenge as JSON to url and expects {"code": "..."}
+// back, so codes can be relayed from a phone automation or chat bot
+func WebhookOTP(url string, timeout time.Duration) OTPProvider {
+	client := &http.Client{Timeout: timeout}
+	return func(challenge OTPChallenge) (string, error) {
+		body, err := json.Marshal(challenge)
+		if err != nil {
+			return "", err
+		}
+
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return "", fmt.Errorf("OTP webhook failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("OTP webhook returned HTTP %d", resp.StatusCode)
+		}
+
+		var result struct {
+			Code string `json:"code"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return "", fmt.Errorf("failed to parse OTP webhook response: %w", err)
+		}
+		if result.Code == "" {
+			return "", fmt.Errorf("OTP webhook returned no code")
+		}
+		return result.Code, nil
+	}
+}
+
+// TOTPProvider generates RFC 6238 codes from a base32 authenticator secret,
+// for accounts that use an authenticator app as the second factor
+func TOTPProvider(secret string) (OTPProvider, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(challenge OTPChallenge) (string, error) {
+		return totpCode(key, time.Now()), nil
+	}, nil
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	return key, nil
+}
+
+// totpCode computes a 6-digit code for the 30-second step containing t
+func totpCode(key []byte, t time.Time) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/30))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
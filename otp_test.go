@@ -0,0 +1,99 @@
+package walmart
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B vectors for the SHA-1 secret, truncated to 6 digits
+	key, err := decodeTOTPSecret("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	if err != nil {
+		t.Fatalf("Failed to decode secret: %v", err)
+	}
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(key, time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("At %d: expected %s, got %s", tt.unix, tt.want, got)
+		}
+	}
+}
+
+func TestRequestOTP(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	manager := NewAuthManager(client)
+
+	if _, err := manager.RequestOTP(OTPChallenge{}); !errors.Is(err, ErrNoOTPProvider) {
+		t.Errorf("Expected ErrNoOTPProvider, got %v", err)
+	}
+
+	var out bytes.Buffer
+	manager.OTP = PromptOTP(strings.NewReader(" 123456 \n"), &out)
+
+	code, err := manager.RequestOTP(OTPChallenge{Channel: "sms", Destination: "***-1234"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if code != "123456" {
+		t.Errorf("Expected code 123456, got %q", code)
+	}
+	if !strings.Contains(out.String(), "***-1234") {
+		t.Errorf("Prompt should mention destination, got %q", out.String())
+	}
+}
+
+func TestWebhookOTP(t *testing.T) {
+	var got OTPChallenge
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"code":"654321"}`))
+	}))
+	defer srv.Close()
+
+	code, err := WebhookOTP(srv.URL, time.Second)(OTPChallenge{Channel: "email", Destination: "j***@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if code != "654321" {
+		t.Errorf("Expected code 654321, got %q", code)
+	}
+	if got.Channel != "email" || got.Destination != "j***@example.com" {
+		t.Errorf("Expected the challenge to be posted, got %+v", got)
+	}
+}
+
+func TestWebhookOTPTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Nobody relays a code before the deadline
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	if _, err := WebhookOTP(srv.URL, 50*time.Millisecond)(OTPChallenge{Channel: "sms"}); err == nil {
+		t.Fatal("Expected the webhook to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to cut the wait short, took %s", elapsed)
+	}
+}
@@ -0,0 +1,110 @@
+package walmart
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// defaultWarmUpPath is a page a browser would plausibly load between API calls
+const defaultWarmUpPath = "/orders"
+
+// PacingProfile spaces requests like a person browsing instead of a fixed ticker
+type PacingProfile struct {
+	MinDelay     time.Duration `json:"min_delay"`      // Shortest gap between requests
+	MaxDelay     time.Duration `json:"max_delay"`      // Longest gap between requests
+	WarmUpChance float64       `json:"warm_up_chance"` // Probability (0-1) of loading a page before a request
+	WarmUpURLs   []string      `json:"warm_up_urls"`   // Pages to load; defaults to the orders page
+}
+
+// HumanPacing is a reasonable profile for long-running syncs
+func HumanPacing() *PacingProfile {
+	return &PacingProfile{
+		MinDelay:     2 * time.Second,
+		MaxDelay:     7 * time.Second,
+		WarmUpChance: 0.1,
+	}
+}
+
+// delay picks a random gap between MinDelay and MaxDelay
+func (p *PacingProfile) delay() time.Duration {
+	if p.MaxDelay <= p.MinDelay {
+		return p.MinDelay
+	}
+	return p.MinDelay + time.Duration(rand.Int63n(int64(p.MaxDelay-p.MinDelay))) //nolint:gosec // jitter needs no crypto randomness
+}
+
+// pace blocks until the next request for operation may be sent. The
+// operation's token bucket always applies, so rate limits and the 429
+// slowdown hold with a pacing profile too; pacing only adds jitter and
+// warm-up loads on top.
+func (c *WalmartClient) pace(operation string) {
+	wait := c.limiters.get(operation).wait()
+	if c.pacing == nil {
+		c.metrics.ObserveRateLimitWait(operation, wait)
+		return
+	}
+
+	// Reserve a slot under the lock and wait outside it, so concurrent
+	// callers are spaced out instead of all firing after one delay
+	c.paceMu.Lock()
+	at := time.Now()
+	if !c.lastRequest.IsZero() {
+		if next := c.lastRequest.Add(c.pacing.delay()); next.After(at) {
+			at = next
+		}
+	}
+	var warmUpGap time.Duration
+	warm := rand.Float64() < c.pacing.WarmUpChance //nolint:gosec // jitter needs no crypto randomness
+	if warm {
+		warmUpGap = c.pacing.delay()
+	}
+	c.lastRequest = at.Add(warmUpGap)
+	c.paceMu.Unlock()
+
+	jitter := time.Until(at)
+	time.Sleep(jitter)
+	if jitter > 0 {
+		wait += jitter
+	}
+	c.metrics.ObserveRateLimitWait(operation, wait)
+	if warm {
+		_ = c.loadPage(c.warmUpURL())
+		time.Sleep(time.Until(at.Add(warmUpGap)))
+	}
+}
+
+// warmUpURL picks one of the pacing profile's warm-up pages
+func (c *WalmartClient) warmUpURL() string {
+	urls := c.pacing.WarmUpURLs
+	if len(urls) == 0 {
+		return c.baseURL + defaultWarmUpPath
+	}
+	return urls[rand.Intn(len(urls))] //nolint:gosec // jitter needs no crypto randomness
+}
+
+// loadPage loads a regular page with the session cookies and keeps any
+// cookies the response rotates
+func (c *WalmartClient) loadPage(pageURL string) error {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return err
+	}
+	c.setDocumentHeaders(req)
+	c.setCookies(req)
+
+	resp, err := c.do(OperationWarmUp, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.updateCookiesFromResponse(resp)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("loading %s returned HTTP %d", pageURL, resp.StatusCode)
+	}
+	return nil
+}
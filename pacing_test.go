@@ -0,0 +1,87 @@
+package walmart
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPacingProfileDelay(t *testing.T) {
+	profile := &PacingProfile{MinDelay: time.Second, MaxDelay: 3 * time.Second}
+	for i := 0; i < 100; i++ {
+		if d := profile.delay(); d < time.Second || d >= 3*time.Second {
+			t.Fatalf("Delay %s outside of range", d)
+		}
+	}
+
+	fixed := &PacingProfile{MinDelay: time.Second}
+	if d := fixed.delay(); d != time.Second {
+		t.Errorf("Expected MinDelay when MaxDelay is unset, got %s", d)
+	}
+}
+
+func TestPacingWarmUp(t *testing.T) {
+	var warmUps int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		warmUps++
+		if r.Header.Get("Cookie") == "" {
+			t.Error("Warm-up request should carry session cookies")
+		}
+		if r.Header.Get("x-apollo-operation-name") != "" || r.Header.Get("sec-fetch-mode") != "navigate" {
+			t.Errorf("Warm-up should look like a page navigation, got %v", r.Header)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "bstc", Value: "rotated"})
+	}))
+	defer server.Close()
+
+	client, _ := NewWalmartClient(ClientConfig{
+		CookieDir: t.TempDir(),
+		RateLimit: time.Millisecond,
+		Pacing: &PacingProfile{
+			MinDelay:     10 * time.Millisecond,
+			MaxDelay:     20 * time.Millisecond,
+			WarmUpChance: 1,
+			WarmUpURLs:   []string{server.URL},
+		},
+	})
+	client.httpClient = server.Client()
+	client.CookieStore.Set("CID", &Cookie{Value: "cid"})
+
+	start := time.Now()
+	client.pace(OperationGetOrder)
+	client.pace(OperationGetOrder)
+
+	if warmUps != 2 {
+		t.Errorf("Expected 2 warm-up requests, got %d", warmUps)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Expected jittered delays between requests, took %s", elapsed)
+	}
+	if cookie := client.CookieStore.Get("bstc"); cookie == nil || cookie.Value != "rotated" {
+		t.Error("Warm-up should pick up rotated cookies")
+	}
+}
+
+func TestPacingKeepsRateLimits(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{
+		CookieDir: t.TempDir(),
+		RateLimit: 40 * time.Millisecond,
+		Pacing:    &PacingProfile{MinDelay: time.Millisecond},
+	})
+
+	start := time.Now()
+	client.pace(OperationGetOrder)
+	client.pace(OperationGetOrder)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected the token bucket to apply under pacing, took %s", elapsed)
+	}
+
+	// A 429 doubles the interval for the operation
+	client.limiters.get(OperationGetOrder).observe(ErrRateLimited)
+	start = time.Now()
+	client.pace(OperationGetOrder)
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected the 429 slowdown to apply under pacing, took %s", elapsed)
+	}
+}
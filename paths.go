@@ -0,0 +1,57 @@
+package walmart
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// DataDirEnv overrides the default storage directory
+const DataDirEnv = "WALMART_API_DIR"
+
+// legacyDirName is the directory used before platform-specific defaults
+const legacyDirName = ".walmart-api"
+
+// appDirName is the directory created inside platform data dirs
+const appDirName = "walmart-api"
+
+// DefaultDataDir is where cookies and state are stored when ClientConfig
+// does not say otherwise: $WALMART_API_DIR if set, an existing ~/.walmart-api,
+// $XDG_DATA_HOME (or ~/.local/share) on Linux, %APPDATA% on Windows, and
+// ~/.walmart-api elsewhere
+func DefaultDataDir() string {
+	home, _ := os.UserHomeDir()
+	return defaultDataDir(runtime.GOOS, os.Getenv, home, dirExists)
+}
+
+func defaultDataDir(goos string, getenv func(string) string, home string, exists func(string) bool) string {
+	if dir := getenv(DataDirEnv); dir != "" {
+		return dir
+	}
+
+	// Keep using the old location so upgrades don't lose the session
+	legacy := filepath.Join(home, legacyDirName)
+	if exists(legacy) {
+		return legacy
+	}
+
+	switch goos {
+	case "windows":
+		if appData := getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, appDirName)
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if dataHome := getenv("XDG_DATA_HOME"); dataHome != "" {
+			return filepath.Join(dataHome, appDirName)
+		}
+		if home != "" {
+			return filepath.Join(home, ".local", "share", appDirName)
+		}
+	}
+	return legacy
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
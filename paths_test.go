@@ -0,0 +1,35 @@
+package walmart
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDataDir(t *testing.T) {
+	home := filepath.FromSlash("/home/me")
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	none := func(string) bool { return false }
+
+	tests := []struct {
+		name     string
+		goos     string
+		vars     map[string]string
+		exists   func(string) bool
+		expected string
+	}{
+		{"override", "linux", map[string]string{DataDirEnv: "/srv/walmart"}, none, "/srv/walmart"},
+		{"legacy dir kept", "linux", map[string]string{"XDG_DATA_HOME": "/xdg"}, func(string) bool { return true }, filepath.Join(home, ".walmart-api")},
+		{"xdg", "linux", map[string]string{"XDG_DATA_HOME": "/xdg"}, none, filepath.Join("/xdg", "walmart-api")},
+		{"xdg default", "linux", nil, none, filepath.Join(home, ".local", "share", "walmart-api")},
+		{"windows", "windows", map[string]string{"APPDATA": `C:\Users\me\AppData\Roaming`}, none, filepath.Join(`C:\Users\me\AppData\Roaming`, "walmart-api")},
+		{"darwin", "darwin", nil, none, filepath.Join(home, ".walmart-api")},
+	}
+
+	for _, tt := range tests {
+		if got := defaultDataDir(tt.goos, env(tt.vars), home, tt.exists); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package walmart
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// ErrTransportConflict is returned when proxy or TLS dialer settings are
+// configured alongside a custom HTTPClient or Transport, which would
+// silently ignore them
+var ErrTransportConflict = errors.New("proxy and TLS dialer settings cannot be combined with a custom HTTPClient or Transport")
+
+// ErrProxyConflict is returned when both Proxy and ProxyFunc are set, since
+// only one of them can pick the proxy
+var ErrProxyConflict = errors.New("cannot set both Proxy and ProxyFunc")
+
+// ProxyFunc picks the proxy for each request, like http.Transport.Proxy
+type ProxyFunc func(req *http.Request) (*url.URL, error)
+
+// RotatingProxy cycles through proxy URLs (http, https or socks5), one per
+// request, e.g. for a residential proxy pool
+func RotatingProxy(proxyURLs ...string) (ProxyFunc, error) {
+	if len(proxyURLs) == 0 {
+		return nil, errors.New("no proxy URLs given")
+	}
+	urls := make([]*url.URL, len(proxyURLs))
+	for i, raw := range proxyURLs {
+		u, err := parseProxyURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		urls[i] = u
+	}
+
+	var next uint64
+	return func(*http.Request) (*url.URL, error) {
+		n := atomic.AddUint64(&next, 1) - 1
+		return urls[n%uint64(len(urls))], nil
+	}, nil
+}
+
+// parseProxyURL accepts the schemes http.Transport can dial through
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// defaultTransport returns the default transport adjusted for the configured
+// proxy and TLS dialer, or nil when neither is set
+func defaultTransport(config ClientConfig) (http.RoundTripper, error) {
+	if config.Proxy != "" && config.ProxyFunc != nil {
+		return nil, ErrProxyConflict
+	}
+	proxy := config.ProxyFunc
+	if config.Proxy != "" {
+		u, err := parseProxyURL(config.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy = func(*http.Request) (*url.URL, error) { return u, nil }
+	}
+	if proxy == nil && config.DialTLSContext == nil {
+		return nil, nil
+	}
+	if config.HTTPClient != nil || config.Transport != nil {
+		return nil, ErrTransportConflict
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = proxy
+	}
+	if config.DialTLSContext != nil {
+		transport.DialTLSContext = config.DialTLSContext
+	}
+	return transport, nil
+}
@@ -0,0 +1,111 @@
+package walmart
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestRotatingProxy(t *testing.T) {
+	var proxies []*server.Server
+	var urls []string
+	for i := 0; i < 2; i++ {
+		srv := server.New()
+		t.Cleanup(srv.Close)
+		srv.On(server.GetOrderHash, nil, `{"data":{"order":{"id":"TEST123"}}}`)
+		proxies = append(proxies, srv)
+		urls = append(urls, srv.URL)
+	}
+
+	rotate, err := RotatingProxy(urls...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An unreachable host proves requests only arrive via the proxies
+	client, err := NewWalmartClient(ClientConfig{
+		CookieDir: t.TempDir(),
+		RateLimit: time.Millisecond,
+		BaseURL:   "http://walmart.invalid",
+		ProxyFunc: rotate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.CookieStore.Set("CID", &Cookie{Value: "test"})
+	client.CookieStore.Set("SPID", &Cookie{Value: "test"})
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.GetOrder("TEST123", true); err != nil {
+			t.Fatalf("GetOrder through proxy failed: %v", err)
+		}
+	}
+	for i, srv := range proxies {
+		if n := len(srv.Requests()); n != 2 {
+			t.Errorf("Proxy %d: expected 2 requests, got %d", i, n)
+		}
+	}
+}
+
+func TestProxyConfigErrors(t *testing.T) {
+	if _, err := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), Proxy: "ftp://proxy:21"}); err == nil {
+		t.Error("Expected an unsupported scheme to be rejected")
+	}
+	if _, err := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), Proxy: "socks5://127.0.0.1:1080"}); err != nil {
+		t.Errorf("Expected socks5 to be accepted, got %v", err)
+	}
+	_, err := NewWalmartClient(ClientConfig{
+		CookieDir: t.TempDir(),
+		Proxy:     "http://127.0.0.1:8080",
+		Transport: http.DefaultTransport,
+	})
+	if !errors.Is(err, ErrTransportConflict) {
+		t.Errorf("Expected ErrTransportConflict, got %v", err)
+	}
+	if _, err := RotatingProxy(); err == nil {
+		t.Error("Expected an empty pool to be rejected")
+	}
+	rotate, _ := RotatingProxy("http://127.0.0.1:8080")
+	_, err = NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), Proxy: "http://127.0.0.1:8081", ProxyFunc: rotate})
+	if !errors.Is(err, ErrProxyConflict) {
+		t.Errorf("Expected ErrProxyConflict, got %v", err)
+	}
+}
+
+func TestDialTLSContext(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"order":{"id":"TEST123"}}}`))
+	}))
+	defer srv.Close()
+
+	// Stands in for a uTLS dialer presenting a browser client hello
+	var dials int
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		return (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, network, addr)
+	}
+
+	client, err := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), BaseURL: srv.URL, DialTLSContext: dial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.CookieStore.Set("CID", &Cookie{Value: "test"})
+	if _, err := client.GetOrder("TEST123", true); err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if dials != 1 {
+		t.Errorf("Expected the custom TLS dialer to be used, got %d dials", dials)
+	}
+
+	_, err = NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), DialTLSContext: dial, HTTPClient: &http.Client{}})
+	if !errors.Is(err, ErrTransportConflict) {
+		t.Errorf("Expected ErrTransportConflict, got %v", err)
+	}
+}
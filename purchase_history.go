@@ -6,9 +6,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"time"
 )
 
+// DefaultPurchaseHistoryLimit is the page size when a request sets no Limit
+const DefaultPurchaseHistoryLimit = 10
+
 // PurchaseHistoryRequest represents the request parameters
 type PurchaseHistoryRequest struct {
 	Cursor       string   `json:"cursor"`       // Empty for first page
@@ -48,6 +50,7 @@ type OrderSummary struct {
 	Status                 *StatusInfo   `json:"status"`
 	Items                  []ItemSummary `json:"items"`
 	DeliveredDate          *string       `json:"deliveredDate"`
+	Classification         string        `json:"classification,omitempty"` // Set by ClientConfig.Classifiers
 }
 
 // StoreInfo represents store information
@@ -71,25 +74,28 @@ type StatusInfo struct {
 
 // ItemSummary represents an item in the order summary
 type ItemSummary struct {
-	ID        string `json:"id"`
-	Quantity  int    `json:"quantity"`
-	Name      string `json:"name"`
-	ImageInfo struct {
-		ThumbnailURL string `json:"thumbnailUrl"`
-	} `json:"imageInfo"`
+	ID        string    `json:"id"`
+	Quantity  int       `json:"quantity"`
+	Name      string    `json:"name"`
+	ImageInfo ImageInfo `json:"imageInfo"`
 }
 
 // GetPurchaseHistory fetches the purchase history with optional filters
 func (c *WalmartClient) GetPurchaseHistory(req PurchaseHistoryRequest) (*PurchaseHistoryResponse, error) {
-	// Rate limiting
-	if !c.lastRequest.IsZero() {
-		<-c.rateLimiter.C
-	}
-	c.lastRequest = time.Now()
+	var resp *PurchaseHistoryResponse
+	err := c.withRetry(OperationPurchaseHistory, func() (err error) {
+		resp, err = c.getPurchaseHistory(req)
+		return err
+	})
+	return resp, err
+}
+
+func (c *WalmartClient) getPurchaseHistory(req PurchaseHistoryRequest) (*PurchaseHistoryResponse, error) {
+	c.pace(OperationPurchaseHistory)
 
 	// Set defaults
 	if req.Limit == 0 {
-		req.Limit = 10
+		req.Limit = DefaultPurchaseHistoryLimit
 	}
 
 	endpoint := c.buildPurchaseHistoryEndpoint(req)
@@ -101,12 +107,15 @@ func (c *WalmartClient) GetPurchaseHistory(req PurchaseHistoryRequest) (*Purchas
 
 	// Set headers (reuse existing method but adjust for purchase history)
 	c.setPurchaseHistoryHeaders(httpReq)
+	if err := c.checkFingerprint(httpReq); err != nil {
+		return nil, err
+	}
 
 	// Set cookies from store
 	c.setCookies(httpReq)
 
 	// Execute request
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(OperationPurchaseHistory, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -123,13 +132,7 @@ func (c *WalmartClient) GetPurchaseHistory(req PurchaseHistoryRequest) (*Purchas
 
 	// Check status
 	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == 429 {
-			return nil, fmt.Errorf("rate limited - cookies might be stale, try refreshing from browser")
-		}
-		if resp.StatusCode == 403 || resp.StatusCode == 418 {
-			return nil, fmt.Errorf("access denied - cookies expired, please update from browser")
-		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError(OperationPurchaseHistory, httpReq, resp, body)
 	}
 
 	// Parse response
@@ -137,6 +140,16 @@ func (c *WalmartClient) GetPurchaseHistory(req PurchaseHistoryRequest) (*Purchas
 	if err := json.Unmarshal(body, &historyResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	if persistedQueryNotFound(body) {
+		return nil, &APIError{
+			Operation:     OperationPurchaseHistory,
+			StatusCode:    resp.StatusCode,
+			CorrelationID: correlationID(httpReq, resp),
+			Kind:          ErrStaleQueryHash,
+		}
+	}
+
+	c.classifyOrders(historyResp.Data.OrderHistoryV2.OrderGroups)
 
 	// Auto-save cookies after successful request
 	_ = c.CookieStore.Save()
@@ -182,8 +195,8 @@ func (c *WalmartClient) GetAllOrders(maxPages int) ([]OrderSummary, error) {
 			break
 		}
 
-		fmt.Printf("Fetched page %d, got %d orders (total: %d)\n",
-			page+1, len(resp.Data.OrderHistoryV2.OrderGroups), len(allOrders))
+		c.logger.Info("fetched purchase history page",
+			"page", page+1, "orders", len(resp.Data.OrderHistoryV2.OrderGroups), "total", len(allOrders))
 	}
 
 	return allOrders, nil
@@ -238,37 +251,11 @@ func (c *WalmartClient) buildPurchaseHistoryEndpoint(req PurchaseHistoryRequest)
 	params := url.Values{}
 	params.Set("variables", string(variablesJSON))
 
-	// Different hash for PurchaseHistoryV2
-	return fmt.Sprintf("https://www.walmart.com/orchestra/cph/graphql/PurchaseHistoryV2/2c3d5a832b56671dca1ed0ec84940f274d0bc80821db4ad7481e496c0ad5847e?%s",
-		params.Encode())
+	return fmt.Sprintf("%s/orchestra/cph/graphql/PurchaseHistoryV2/%s?%s",
+		c.baseURL, c.queryHash(OperationPurchaseHistory), params.Encode())
 }
 
 // Set headers specific to purchase history
 func (c *WalmartClient) setPurchaseHistoryHeaders(req *http.Request) {
-	headers := map[string]string{
-		"accept":                  "application/json",
-		"accept-language":         "en-US",
-		"content-type":            "application/json",
-		"user-agent":              "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
-		"x-apollo-operation-name": "PurchaseHistoryV2",
-		"x-o-gql-query":           "query PurchaseHistoryV2",
-		"x-o-platform":            "rweb",
-		"x-o-bu":                  "WALMART-US",
-		"x-o-mart":                "B2C",
-		"x-o-segment":             "oaoh",
-		"x-o-correlation-id":      fmt.Sprintf("walmart-go-%d", time.Now().Unix()),
-		"wm_qos.correlation_id":   fmt.Sprintf("walmart-go-%d", time.Now().Unix()),
-		"wm_mp":                   "true",
-		"sec-fetch-site":          "same-origin",
-		"sec-fetch-mode":          "cors",
-		"sec-fetch-dest":          "empty",
-		"dnt":                     "1",
-		"x-o-platform-version":    "usweb-1.221.0",
-		"x-enable-server-timing":  "1",
-		"x-latency-trace":         "1",
-	}
-
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
+	c.setOperationHeaders(req, OperationPurchaseHistory)
 }
@@ -0,0 +1,80 @@
+package walmart
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Reference matrix for "https://walmart.com" at version 2, level M, mask 3
+var qrGolden = []string{
+	"#######.######..#.#######",
+	"#.....#.##.###..#.#.....#",
+	"#.###.#....#.####.#.###.#",
+	"#.###.#.#.######..#.###.#",
+	"#.###.#..###.####.#.###.#",
+	"#.....#..#.#.##.#.#.....#",
+	"#######.#.#.#.#.#.#######",
+	"........#..#...##........",
+	"#.##.###..##.#.##.#..#.##",
+	"#.##....#..#.#.....#...#.",
+	"##.#..#....#.#...#.#.....",
+	"#.#....##..#.....#...##..",
+	".#..#.#.#####.#######.###",
+	".#...#.#.#.##.#######...#",
+	".##.###....#.#..###.#.##.",
+	"#.###...#.##..#...###...#",
+	".....###.##.#.#.#########",
+	"........#.#...###...#.#.#",
+	"#######.###..##.#.#.#.###",
+	"#.....#.#.##.####...#...#",
+	"#.###.#......##.######...",
+	"#.###.#.#........##.#####",
+	"#.###.#.##....#..##.#.##.",
+	"#.....#...#####..##.#.#..",
+	"#######.####.....########",
+}
+
+func TestEncodeQRMatchesReference(t *testing.T) {
+	text := "https://walmart.com"
+	code := newQRCode(2)
+	code.placeData(qrInterleave(qrVersions[2], qrData([]byte(text), 2)))
+	code.applyMask(3)
+	code.drawFormat(3)
+
+	for y, row := range qrGolden {
+		for x, c := range row {
+			if code.modules[y][x] != (c == '#') {
+				t.Fatalf("Module (%d, %d) differs from the reference", x, y)
+			}
+		}
+	}
+
+	picked, err := encodeQR(text)
+	if err != nil || picked.size != len(qrGolden) {
+		t.Fatalf("Expected version 2 for %q, got %+v, %v", text, picked, err)
+	}
+}
+
+func TestWriteQRCode(t *testing.T) {
+	var out bytes.Buffer
+	url := "http://192.168.1.10:54321/relay/0123456789abcdef0123456789abcdef"
+	if err := writeQRCode(&out, url); err != nil {
+		t.Fatalf("writeQRCode failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	width := 37 + 2*qrQuietZone // Version 5
+	if len(lines) != (width+1)/2 {
+		t.Errorf("Expected %d lines, got %d", (width+1)/2, len(lines))
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Fatalf("Expected %d columns, got %d", width, n)
+		}
+	}
+
+	if err := writeQRCode(&out, strings.Repeat("x", 300)); err == nil {
+		t.Error("Expected an error for text beyond version 10")
+	}
+}
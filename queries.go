@@ -0,0 +1,149 @@
+package walmart
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/eshaffer321/walmart-client-go/internal/queryhash"
+)
+
+// OperationDiscoverHashes is reported to hooks and metrics for the page and
+// bundle loads made by DiscoverQueryHashes
+const OperationDiscoverHashes = "discoverQueryHashes"
+
+const (
+	maxDiscoveryScripts = 40
+	maxBundleSize       = 20 << 20
+)
+
+var scriptSrcPattern = regexp.MustCompile(`<script[^>]+src=["']([^"']+\.js[^"']*)["']`)
+
+// DefaultQueryHashes returns the persisted-query hashes the web app used
+// when this version was released, keyed by operation name
+func DefaultQueryHashes() map[string]string {
+	return map[string]string{
+		OperationGetOrder:        queryhash.GetOrder,
+		OperationPurchaseHistory: queryhash.PurchaseHistory,
+	}
+}
+
+// SetQueryHashes replaces the hashes of the given operations; others keep
+// their current hash
+func (c *WalmartClient) SetQueryHashes(hashes map[string]string) {
+	c.hashMu.Lock()
+	defer c.hashMu.Unlock()
+	if c.queryHashes == nil {
+		c.queryHashes = DefaultQueryHashes()
+	}
+	for op, hash := range hashes {
+		c.queryHashes[op] = hash
+	}
+}
+
+// QueryHashes returns the hashes requests are currently sent with
+func (c *WalmartClient) QueryHashes() map[string]string {
+	c.hashMu.RLock()
+	defer c.hashMu.RUnlock()
+	hashes := make(map[string]string, len(c.queryHashes))
+	for op, hash := range c.queryHashes {
+		hashes[op] = hash
+	}
+	return hashes
+}
+
+func (c *WalmartClient) queryHash(operation string) string {
+	c.hashMu.RLock()
+	defer c.hashMu.RUnlock()
+	if hash, ok := c.queryHashes[operation]; ok {
+		return hash
+	}
+	return DefaultQueryHashes()[operation]
+}
+
+// DiscoverQueryHashes loads the orders page, scans its JavaScript bundles
+// for the getOrder and PurchaseHistoryV2 hashes and switches to the ones it
+// finds. The bundle layout is undocumented: a hash is recognised as a
+// 64-character hex string shortly after the quoted operation name, as in
+// Apollo's persisted query manifests. When nothing is found, set
+// ClientConfig.QueryHashes by hand from a request in the browser's network
+// tab.
+func (c *WalmartClient) DiscoverQueryHashes() (map[string]string, error) {
+	pageURL := c.baseURL + defaultWarmUpPath
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setDocumentHeaders(req)
+	c.setCookies(req)
+
+	page, err := c.fetchDiscovery(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load orders page: %w", err)
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []string{OperationGetOrder, OperationPurchaseHistory}
+	found := findQueryHashes(page, operations)
+	scripts := scriptSrcPattern.FindAllSubmatch(page, maxDiscoveryScripts)
+	for _, m := range scripts {
+		if len(found) == len(operations) {
+			break
+		}
+		src, err := base.Parse(string(m[1]))
+		if err != nil {
+			continue
+		}
+		req, err := http.NewRequest("GET", src.String(), nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("user-agent", c.HeaderProfile().Headers["user-agent"])
+		bundle, err := c.fetchDiscovery(req)
+		if err != nil {
+			c.logger.Debug("skipping bundle", "url", src.String(), "error", err)
+			continue
+		}
+		for op, hash := range findQueryHashes(bundle, operations) {
+			found[op] = hash
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no query hashes found in %d bundles of the orders page", len(scripts))
+	}
+	c.SetQueryHashes(found)
+	c.logger.Info("discovered query hashes", "hashes", found)
+	return found, nil
+}
+
+func (c *WalmartClient) fetchDiscovery(req *http.Request) ([]byte, error) {
+	resp, err := c.do(OperationDiscoverHashes, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxBundleSize))
+}
+
+// findQueryHashes finds the first hash following each quoted operation name
+func findQueryHashes(source []byte, operations []string) map[string]string {
+	found := make(map[string]string)
+	for _, op := range operations {
+		pattern := regexp.MustCompile(`["'/]` + regexp.QuoteMeta(op) + `["'/][^;}]{0,200}?\b([0-9a-f]{64})\b`)
+		if m := pattern.FindSubmatch(source); m != nil {
+			found[op] = string(m[1])
+		}
+	}
+	return found
+}
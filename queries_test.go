@@ -0,0 +1,92 @@
+package walmart
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+const newGetOrderHash = "1111111111111111111111111111111111111111111111111111111111111111"
+
+func TestStaleQueryHash(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	srv.On(newGetOrderHash, nil, `{"data":{"order":{"id":"TEST123"}}}`)
+
+	_, err := client.GetOrder("TEST123", true)
+	if !errors.Is(err, ErrStaleQueryHash) {
+		t.Fatalf("Expected ErrStaleQueryHash, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "getOrder query hash") {
+		t.Errorf("Expected the operation in the message, got %q", err.Error())
+	}
+
+	client.SetQueryHashes(map[string]string{OperationGetOrder: newGetOrderHash})
+	if _, err := client.GetOrder("TEST123", true); err != nil {
+		t.Fatalf("GetOrder with the new hash failed: %v", err)
+	}
+	if hashes := client.QueryHashes(); hashes[OperationPurchaseHistory] != server.PurchaseHistoryHash {
+		t.Errorf("Expected other hashes to be kept, got %v", hashes)
+	}
+}
+
+func TestQueryHashesConfig(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{
+		CookieDir:   t.TempDir(),
+		QueryHashes: map[string]string{OperationPurchaseHistory: newGetOrderHash},
+	})
+	endpoint := client.buildPurchaseHistoryEndpoint(PurchaseHistoryRequest{})
+	if !strings.Contains(endpoint, "/PurchaseHistoryV2/"+newGetOrderHash+"?") {
+		t.Errorf("Expected the configured hash, got %s", endpoint)
+	}
+}
+
+func TestDiscoverQueryHashes(t *testing.T) {
+	const historyHash = "2222222222222222222222222222222222222222222222222222222222222222"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-o-gql-query") != "" || r.Header.Get("sec-fetch-dest") != "document" {
+			t.Errorf("Expected document navigation headers, got %v", r.Header)
+		}
+		_, _ = w.Write([]byte(`<html><script src="/static/vendor.js"></script><script defer src="/static/orders.js?v=2"></script></html>`))
+	})
+	mux.HandleFunc("/static/vendor.js", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/static/orders.js", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`var m={"getOrderDetails":"x";"getOrder":{"sha256Hash":"` + newGetOrderHash +
+			`"},"PurchaseHistoryV2":{"version":1,"sha256Hash":"` + historyHash + `"}};`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), BaseURL: srv.URL})
+	found, err := client.DiscoverQueryHashes()
+	if err != nil {
+		t.Fatalf("DiscoverQueryHashes failed: %v", err)
+	}
+	if found[OperationGetOrder] != newGetOrderHash || found[OperationPurchaseHistory] != historyHash {
+		t.Errorf("Unexpected hashes: %v", found)
+	}
+	if !strings.Contains(client.buildOrderEndpoint("1", false), newGetOrderHash) {
+		t.Error("Expected requests to use the discovered hash")
+	}
+}
+
+func TestDiscoverQueryHashesNothingFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html></html>`))
+	}))
+	defer srv.Close()
+
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir(), BaseURL: srv.URL})
+	if _, err := client.DiscoverQueryHashes(); err == nil {
+		t.Fatal("Expected an error when no hashes are found")
+	}
+	if client.queryHash(OperationGetOrder) != DefaultQueryHashes()[OperationGetOrder] {
+		t.Error("Expected the default hash to be kept")
+	}
+}
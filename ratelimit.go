@@ -0,0 +1,113 @@
+package walmart
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxSlowdown caps how far repeated 429s stretch an endpoint's interval
+const maxSlowdown = 16
+
+// RateLimit is a token bucket: one request per Interval on average, with up
+// to Burst requests allowed back to back after a quiet period
+type RateLimit struct {
+	Interval time.Duration `json:"interval"`
+	Burst    int           `json:"burst"`
+}
+
+// tokenBucket is safe for concurrent use. Callers reserve a token under the
+// lock and sleep outside it, so waiters queue in reservation order.
+type tokenBucket struct {
+	mu       sync.Mutex
+	limit    RateLimit
+	tokens   float64
+	last     time.Time
+	slowdown float64 // Interval multiplier, raised after 429s
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst), slowdown: 1}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	interval := time.Duration(float64(b.limit.Interval) * b.slowdown)
+	if interval <= 0 {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(interval)
+		if b.tokens > float64(b.limit.Burst) {
+			b.tokens = float64(b.limit.Burst)
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(interval))
+}
+
+// wait blocks until a token is available and returns how long that took
+func (b *tokenBucket) wait() time.Duration {
+	d := b.reserve(time.Now())
+	if d > 0 {
+		time.Sleep(d)
+	}
+	return d
+}
+
+// observe doubles the interval after a 429 and eases back on success
+func (b *tokenBucket) observe(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		b.slowdown *= 2
+		if b.slowdown > maxSlowdown {
+			b.slowdown = maxSlowdown
+		}
+	case err == nil && b.slowdown > 1:
+		b.slowdown /= 2
+		if b.slowdown < 1 {
+			b.slowdown = 1
+		}
+	}
+}
+
+// limiters hands out one bucket per operation
+type limiters struct {
+	mu       sync.Mutex
+	fallback RateLimit
+	limits   map[string]RateLimit
+	buckets  map[string]*tokenBucket
+}
+
+func (l *limiters) get(operation string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[operation]; ok {
+		return b
+	}
+	limit, ok := l.limits[operation]
+	if !ok {
+		limit = l.fallback
+	}
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+	b := newTokenBucket(limit)
+	l.buckets[operation] = b
+	return b
+}
@@ -0,0 +1,90 @@
+package walmart
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Interval: time.Second, Burst: 3})
+	now := time.Date(2025, 9, 5, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if d := bucket.reserve(now); d != 0 {
+			t.Fatalf("Request %d should use the burst, waited %s", i+1, d)
+		}
+	}
+	if d := bucket.reserve(now); d != time.Second {
+		t.Errorf("Expected to wait 1s once the burst is spent, got %s", d)
+	}
+	if d := bucket.reserve(now); d != 2*time.Second {
+		t.Errorf("Expected queued reservations to stack, got %s", d)
+	}
+
+	// A long pause refills no more than the burst
+	later := now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if d := bucket.reserve(later); d != 0 {
+			t.Fatalf("Expected a refilled burst, request %d waited %s", i+1, d)
+		}
+	}
+	if d := bucket.reserve(later); d == 0 {
+		t.Error("Expected the refill to be capped at the burst")
+	}
+}
+
+func TestTokenBucketSlowsDownAfter429(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Interval: time.Second})
+	now := time.Date(2025, 9, 5, 12, 0, 0, 0, time.UTC)
+	bucket.reserve(now)
+
+	bucket.observe(&APIError{StatusCode: 429, Kind: ErrRateLimited})
+	if d := bucket.reserve(now); d != 2*time.Second {
+		t.Errorf("Expected the interval to double after a 429, got %s", d)
+	}
+
+	bucket.observe(nil)
+	if bucket.slowdown != 1 {
+		t.Errorf("Expected a success to ease back, got slowdown %v", bucket.slowdown)
+	}
+	bucket.observe(&APIError{StatusCode: 403, Kind: ErrSessionExpired})
+	if bucket.slowdown != 1 {
+		t.Error("Only 429s should slow the bucket down")
+	}
+}
+
+func TestPerOperationLimits(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{
+		CookieDir:  t.TempDir(),
+		RateLimit:  time.Hour,
+		RateLimits: map[string]RateLimit{OperationPurchaseHistory: {Interval: time.Millisecond, Burst: 5}},
+	})
+
+	if client.limiters.get(OperationGetOrder) == client.limiters.get(OperationPurchaseHistory) {
+		t.Fatal("Expected separate buckets per operation")
+	}
+
+	// Concurrent callers share the history bucket without racing
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.pace(OperationPurchaseHistory)
+		}()
+	}
+	wg.Wait()
+
+	// The first getOrder is free even though its interval is an hour
+	done := make(chan struct{})
+	go func() {
+		client.pace(OperationGetOrder)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("First getOrder should not wait")
+	}
+}
@@ -0,0 +1,101 @@
+package walmart
+
+import "time"
+
+// Receipt is a purchase regardless of how it was obtained: the order API,
+// an e-receipt, a parsed email or OCR. Exporters and analytics should
+// consume this rather than a specific source type.
+type Receipt interface {
+	ReceiptID() string
+	ReceiptSource() string         // e.g. "api", "email", "ocr"
+	OrderTime() (time.Time, error) // When the purchase was made
+	ChargedTotal() (float64, bool) // Amount paid, if known
+	ReceiptLines() []ReceiptLine   // Purchased lines
+}
+
+// ReceiptLine is one purchased line on a Receipt
+type ReceiptLine struct {
+	ItemID   string  `json:"itemId,omitempty"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Amount   float64 `json:"amount"`
+}
+
+// Receipt sources
+const (
+	ReceiptSourceAPI = "api"
+)
+
+// ReceiptID implements Receipt
+func (o *Order) ReceiptID() string {
+	return o.ID
+}
+
+// ReceiptSource implements Receipt
+func (o *Order) ReceiptSource() string {
+	return ReceiptSourceAPI
+}
+
+// ReceiptLines implements Receipt, leaving out deposits and bag fees
+func (o *Order) ReceiptLines() []ReceiptLine {
+	var lines []ReceiptLine
+	for _, item := range o.GetItems() {
+		if item.ChargeKind() != "" {
+			continue
+		}
+		amount, _ := item.LinePrice()
+		lines = append(lines, ReceiptLine{
+			ItemID:   item.USItemID(),
+			Name:     item.Name(),
+			Quantity: item.Quantity,
+			Amount:   amount,
+		})
+	}
+	return lines
+}
+
+// BasicReceipt is a Receipt for sources without a richer model, such as
+// parsed emails, OCR results or other retailers' exports
+type BasicReceipt struct {
+	ID     string        `json:"id"`
+	Source string        `json:"source"`
+	Time   time.Time     `json:"time"`
+	Total  *float64      `json:"total,omitempty"`
+	Lines  []ReceiptLine `json:"lines"`
+}
+
+// ReceiptID implements Receipt
+func (r *BasicReceipt) ReceiptID() string { return r.ID }
+
+// ReceiptSource implements Receipt
+func (r *BasicReceipt) ReceiptSource() string { return r.Source }
+
+// OrderTime implements Receipt
+func (r *BasicReceipt) OrderTime() (time.Time, error) { return r.Time, nil }
+
+// ChargedTotal implements Receipt, summing the lines when no total is known
+func (r *BasicReceipt) ChargedTotal() (float64, bool) {
+	if r.Total != nil {
+		return *r.Total, true
+	}
+	if len(r.Lines) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, line := range r.Lines {
+		total += line.Amount
+	}
+	return total, true
+}
+
+// ReceiptLines implements Receipt
+func (r *BasicReceipt) ReceiptLines() []ReceiptLine { return r.Lines }
+
+// OrdersAsReceipts adapts API orders for Receipt consumers
+func OrdersAsReceipts(orders []*Order) []Receipt {
+	receipts := make([]Receipt, len(orders))
+	for i, order := range orders {
+		receipts[i] = order
+	}
+	return receipts
+}
@@ -0,0 +1,57 @@
+package walmart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderAsReceipt(t *testing.T) {
+	order := &Order{
+		ID:           "123",
+		OrderDate:    "2024-03-10T12:00:00.000-0700",
+		PriceDetails: &OrderPriceDetails{GrandTotal: &PriceLineItem{Value: 5.58}},
+		Groups: []OrderGroup{{Items: []OrderItem{
+			{Quantity: 1, ProductInfo: &ProductInfo{Name: "Soda", USItemID: "42"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 4.98}}},
+			{Quantity: 1, ProductInfo: &ProductInfo{Name: "CA CRV"}, PriceInfo: &ItemPrice{LinePrice: &Price{Value: 0.60}}},
+		}}},
+	}
+
+	var receipt Receipt = order
+	if receipt.ReceiptID() != "123" || receipt.ReceiptSource() != ReceiptSourceAPI {
+		t.Errorf("Unexpected identity: %s/%s", receipt.ReceiptID(), receipt.ReceiptSource())
+	}
+	if total, ok := receipt.ChargedTotal(); !ok || total != 5.58 {
+		t.Errorf("Unexpected total: %.2f %v", total, ok)
+	}
+
+	lines := receipt.ReceiptLines()
+	if len(lines) != 1 || lines[0].ItemID != "42" || lines[0].Amount != 4.98 {
+		t.Errorf("Expected only the merchandise line, got %+v", lines)
+	}
+}
+
+func TestBasicReceipt(t *testing.T) {
+	receipt := &BasicReceipt{
+		ID:     "email-1",
+		Source: "email",
+		Time:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Lines:  []ReceiptLine{{Name: "Milk", Quantity: 1, Amount: 3.5}, {Name: "Eggs", Quantity: 1, Amount: 4}},
+	}
+
+	if total, ok := receipt.ChargedTotal(); !ok || total != 7.5 {
+		t.Errorf("Expected summed lines, got %.2f %v", total, ok)
+	}
+
+	paid := 8.0
+	receipt.Total = &paid
+	if total, _ := receipt.ChargedTotal(); total != 8 {
+		t.Errorf("Expected explicit total, got %.2f", total)
+	}
+
+	if _, ok := (&BasicReceipt{}).ChargedTotal(); ok {
+		t.Error("Empty receipt should have no total")
+	}
+	if receipts := OrdersAsReceipts([]*Order{{ID: "1"}}); receipts[0].ReceiptID() != "1" {
+		t.Error("Expected orders to adapt to receipts")
+	}
+}
@@ -0,0 +1,185 @@
+package walmart
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRelayBody caps pasted payloads; a full curl capture is well under this
+const maxRelayBody = 1 << 20
+
+// CookieRelay serves a one-time local web page where cookies (or a whole
+// "Copy as cURL" capture) can be pasted from another device such as a phone.
+// This avoids copying curl files onto headless servers by hand.
+type CookieRelay struct {
+	client   *WalmartClient
+	token    string
+	received chan struct{}
+	once     sync.Once
+}
+
+var relayPage = template.Must(template.New("relay").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Walmart cookie relay</title>
+</head>
+<body>
+{{if .Done}}
+<p>Cookies received ({{.Count}}). You can close this page.</p>
+{{else}}
+<p>Paste a "Copy as cURL" capture or the raw Cookie header from walmart.com.</p>
+{{if .Error}}<p><strong>{{.Error}}</strong></p>{{end}}
+<form method="POST">
+<textarea name="cookies" rows="12" style="width:100%"></textarea>
+<button type="submit">Send</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// NewCookieRelay creates a relay protected by a random one-time token
+func (c *WalmartClient) NewCookieRelay() (*CookieRelay, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate relay token: %w", err)
+	}
+
+	return &CookieRelay{
+		client:   c,
+		token:    hex.EncodeToString(buf),
+		received: make(chan struct{}),
+	}, nil
+}
+
+// Path returns the secret URL path the relay page is served on
+func (r *CookieRelay) Path() string {
+	return "/relay/" + r.token
+}
+
+// Done is closed once cookies have been received and saved
+func (r *CookieRelay) Done() <-chan struct{} {
+	return r.received
+}
+
+// ServeHTTP renders the paste form and ingests submitted cookies
+func (r *CookieRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != r.Path() {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	switch req.Method {
+	case http.MethodGet:
+		_ = relayPage.Execute(w, map[string]interface{}{})
+	case http.MethodPost:
+		req.Body = http.MaxBytesReader(w, req.Body, maxRelayBody)
+		if err := req.ParseForm(); err != nil {
+			http.Error(w, "invalid form", http.StatusBadRequest)
+			return
+		}
+
+		cookies := parsePastedCookies(req.PostFormValue("cookies"))
+		if len(cookies) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = relayPage.Execute(w, map[string]interface{}{"Error": "No cookies found in pasted text"})
+			return
+		}
+
+		if err := r.client.importCookies(cookies, "relay"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		r.once.Do(func() { close(r.received) })
+		_ = relayPage.Execute(w, map[string]interface{}{"Done": true, "Count": len(cookies)})
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ServeCookieRelay listens on addr and blocks until cookies have been
+// pasted or the timeout expires (0 waits forever). The relay URL is logged;
+// when out is non-nil it also gets a QR code of the URL to scan with a
+// phone.
+func (c *WalmartClient) ServeCookieRelay(addr string, timeout time.Duration, out io.Writer) error {
+	relay, err := c.NewCookieRelay()
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start relay: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           relay,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	relayURL := "http://" + relayHost(ln.Addr()) + relay.Path()
+	c.logger.Info("cookie relay waiting for a paste", "url", relayURL)
+	if out != nil {
+		_, _ = fmt.Fprintln(out, "Scan this code or open the URL on a device logged into walmart.com and paste your cookies:")
+		if err := writeQRCode(out, relayURL); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "  %s\n", relayURL)
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-relay.Done():
+		return nil
+	case <-expired:
+		return fmt.Errorf("cookie relay timed out after %s", timeout)
+	}
+}
+
+// relayHost turns a wildcard listen address into one reachable from the LAN
+func relayHost(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return addr.String()
+	}
+
+	ifaceAddrs, _ := net.InterfaceAddrs()
+	for _, a := range ifaceAddrs {
+		if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return net.JoinHostPort(ipNet.IP.String(), fmt.Sprint(tcpAddr.Port))
+		}
+	}
+	return net.JoinHostPort("localhost", fmt.Sprint(tcpAddr.Port))
+}
+
+// parsePastedCookies accepts either a curl capture or a raw Cookie header
+func parsePastedCookies(text string) map[string]string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "curl ") {
+		return extractCookiesFromCurl(text)
+	}
+	text = strings.TrimPrefix(text, "Cookie:")
+	text = strings.TrimPrefix(text, "cookie:")
+	return parseCookieHeader(text)
+}
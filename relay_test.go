@@ -0,0 +1,84 @@
+package walmart
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieRelay(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+
+	relay, err := client.NewCookieRelay()
+	if err != nil {
+		t.Fatalf("Failed to create relay: %v", err)
+	}
+
+	// Wrong token must not expose the form
+	rec := httptest.NewRecorder()
+	relay.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/relay/guess", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for wrong token, got %d", rec.Code)
+	}
+
+	// Empty paste is rejected
+	rec = httptest.NewRecorder()
+	relay.ServeHTTP(rec, relayPost(relay.Path(), "nothing useful"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty paste, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	relay.ServeHTTP(rec, relayPost(relay.Path(), "Cookie: CID=abc; SPID=def=="))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	select {
+	case <-relay.Done():
+	default:
+		t.Error("Relay should be done after a successful paste")
+	}
+
+	cid := client.CookieStore.Get("CID")
+	if cid == nil || cid.Value != "abc" || cid.Source != "relay" || !cid.Essential {
+		t.Errorf("CID not imported correctly: %+v", cid)
+	}
+
+	if spid := client.CookieStore.Get("SPID"); spid == nil || spid.Value != "def==" {
+		t.Errorf("SPID not imported correctly: %+v", spid)
+	}
+}
+
+func TestServeCookieRelayWritesQRCode(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+
+	var out bytes.Buffer
+	err := client.ServeCookieRelay("127.0.0.1:0", 10*time.Millisecond, &out)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	if !strings.Contains(out.String(), "█") || !strings.Contains(out.String(), "http://127.0.0.1:") {
+		t.Errorf("Expected a QR code and the URL, got:\n%s", out.String())
+	}
+}
+
+func TestParsePastedCookiesCurl(t *testing.T) {
+	cookies := parsePastedCookies(`curl 'https://www.walmart.com/orders' \
+  -b 'CID=abc; auth=xyz'`)
+
+	if cookies["CID"] != "abc" || cookies["auth"] != "xyz" {
+		t.Errorf("Unexpected cookies from curl paste: %v", cookies)
+	}
+}
+
+func relayPost(path, text string) *http.Request {
+	form := url.Values{"cookies": {text}}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
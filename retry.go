@@ -0,0 +1,94 @@
+package walmart
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy retries transient failures: 5xx responses, network errors and
+// 429s. Session errors and bot challenges are never retried.
+type RetryPolicy struct {
+	MaxAttempts int           `json:"max_attempts"` // Total attempts including the first
+	BaseDelay   time.Duration `json:"base_delay"`   // Delay before the first retry, doubled each time
+	MaxDelay    time.Duration `json:"max_delay"`    // Cap on backoff; a longer Retry-After gives up instead
+	Jitter      float64       `json:"jitter"`       // Fraction (0-1) of each delay to randomize
+}
+
+// DefaultRetryPolicy suits nightly syncs that should ride out brief outages
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// retryDelay returns how long to wait before attempt (1-based) is retried,
+// or false when err should not be retried
+func (p *RetryPolicy) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !isTransient(err) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if p.MaxDelay > 0 && apiErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		return apiErr.RetryAfter, true
+	}
+
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		spread := time.Duration(float64(delay) * p.Jitter)
+		if spread > 0 {
+			delay += time.Duration(rand.Int63n(int64(2*spread))) - spread //nolint:gosec // jitter needs no crypto randomness
+		}
+	}
+	return delay, true
+}
+
+// isTransient reports whether err is worth retrying
+func isTransient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind == ErrRateLimited || apiErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// withRetry runs fn under the configured retry policy, recording every
+// attempt in the operation stats and rate limiter. A suggested cool-down
+// ends the retries.
+func (c *WalmartClient) withRetry(operation string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err != nil {
+			c.reportError(operation, err)
+		}
+		c.limiters.get(operation).observe(err)
+		err = c.recordOutcome(operation, err)
+		if err == nil {
+			return nil
+		}
+
+		var coolDown *CoolDownError
+		if errors.As(err, &coolDown) {
+			return err
+		}
+		delay, ok := c.retry.retryDelay(attempt, err)
+		if !ok {
+			return err
+		}
+		c.metrics.IncRetry(operation)
+		c.logger.Info("retrying walmart request", "operation", operation, "attempt", attempt+1, "delay", delay, "error", err)
+		time.Sleep(delay)
+	}
+}
@@ -0,0 +1,86 @@
+package walmart
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestGetOrderRetriesTransientFailures(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	client.retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderId": "TEST123"},
+		`{"data":{"order":{"id":"TEST123"}}}`)
+	srv.Enqueue(server.Response{Status: http.StatusBadGateway}, server.RateLimited(""))
+
+	order, err := client.GetOrder("TEST123", true)
+	if err != nil || order.ID != "TEST123" {
+		t.Fatalf("Expected success on the third attempt, got %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+	if stats := client.Stats()[OperationGetOrder]; stats.Requests != 3 || stats.Failures != 2 {
+		t.Errorf("Expected every attempt in stats, got %+v", stats)
+	}
+}
+
+func TestGetOrderDoesNotRetrySessionErrors(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	client.retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	srv.Enqueue(server.BotChallenge())
+
+	if _, err := client.GetOrder("TEST123", true); !errors.Is(err, ErrBotChallenge) {
+		t.Errorf("Expected ErrBotChallenge, got %v", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("Expected a single request, got %d", n)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	transient := &APIError{StatusCode: http.StatusBadGateway}
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second} {
+		if got, ok := policy.retryDelay(attempt, transient); !ok || got != want {
+			t.Errorf("Attempt %d: expected %s, got %s (%v)", attempt, want, got, ok)
+		}
+	}
+	if _, ok := policy.retryDelay(4, transient); ok {
+		t.Error("Expected no retry after MaxAttempts")
+	}
+
+	limited := &APIError{StatusCode: http.StatusTooManyRequests, Kind: ErrRateLimited, RetryAfter: 2 * time.Second}
+	if got, ok := policy.retryDelay(1, limited); !ok || got != 2*time.Second {
+		t.Errorf("Expected Retry-After to win, got %s", got)
+	}
+	limited.RetryAfter = time.Hour
+	if _, ok := policy.retryDelay(1, limited); ok {
+		t.Error("Expected to give up when Retry-After exceeds MaxDelay")
+	}
+
+	if _, ok := policy.retryDelay(1, &APIError{StatusCode: http.StatusForbidden, Kind: ErrSessionExpired}); ok {
+		t.Error("Session errors must not be retried")
+	}
+	var none *RetryPolicy
+	if _, ok := none.retryDelay(1, transient); ok {
+		t.Error("A nil policy must not retry")
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	now := time.Date(2025, 9, 5, 12, 0, 0, 0, time.UTC)
+	if got := retryAfter("5", now); got != 5*time.Second {
+		t.Errorf("Expected 5s, got %s", got)
+	}
+	if got := retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); got != time.Minute {
+		t.Errorf("Expected 1m from an HTTP date, got %s", got)
+	}
+	if got := retryAfter("soon", now); got != 0 {
+		t.Errorf("Expected 0 for garbage, got %s", got)
+	}
+}
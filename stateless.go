@@ -0,0 +1,56 @@
+package walmart
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables read in Stateless mode
+const (
+	CookiesEnv     = "WALMART_COOKIES"      // Cookie header or curl command
+	CookiesFileEnv = "WALMART_COOKIES_FILE" // Mounted secret holding a cookie header, curl command or cookies.json
+)
+
+// loadCookiesFromEnv imports session material supplied by the container
+// environment. Cookies already loaded from a configured state dir are kept
+// unless the environment provides the same names.
+func (c *WalmartClient) loadCookiesFromEnv() error {
+	if path := os.Getenv(CookiesFileEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", CookiesFileEnv, err)
+		}
+		if err := c.importCookieMaterial(string(data)); err != nil {
+			return fmt.Errorf("failed to import %s: %w", CookiesFileEnv, err)
+		}
+	}
+
+	if value := os.Getenv(CookiesEnv); value != "" {
+		if err := c.importCookieMaterial(value); err != nil {
+			return fmt.Errorf("failed to import %s: %w", CookiesEnv, err)
+		}
+	}
+	return nil
+}
+
+// importCookieMaterial accepts a saved cookies.json, a curl command or a
+// Cookie header
+func (c *WalmartClient) importCookieMaterial(text string) error {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") {
+		cookies := parsePastedCookies(text)
+		if len(cookies) == 0 {
+			return fmt.Errorf("no cookies found")
+		}
+		return c.importCookies(cookies, "env")
+	}
+
+	var saved CookieStore
+	if err := json.Unmarshal([]byte(text), &saved); err != nil {
+		return fmt.Errorf("invalid cookie store: %w", err)
+	}
+	c.CookieStore.mergeFrom(&saved)
+	return c.CookieStore.Save()
+}
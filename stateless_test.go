@@ -0,0 +1,60 @@
+package walmart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatelessFromEnv(t *testing.T) {
+	t.Setenv(CookiesEnv, "CID=env_cid; SPID=env_spid")
+	t.Setenv(DataDirEnv, filepath.Join(t.TempDir(), "should-not-exist"))
+
+	client, err := NewWalmartClient(ClientConfig{Stateless: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	cid := client.CookieStore.Get("CID")
+	if cid == nil || cid.Value != "env_cid" || cid.Source != "env" || !cid.Essential {
+		t.Errorf("Unexpected CID cookie: %+v", cid)
+	}
+	if client.CookieStore.FilePath != "" {
+		t.Errorf("Stateless client should have no cookie file, got %s", client.CookieStore.FilePath)
+	}
+
+	// Rotations stay in memory
+	client.CookieStore.Set("CID", &Cookie{Value: "rotated"})
+	if err := client.CookieStore.Save(); err != nil {
+		t.Errorf("Save should be a no-op, got %v", err)
+	}
+	if _, err := os.Stat(os.Getenv(DataDirEnv)); !os.IsNotExist(err) {
+		t.Error("Stateless client should not create a data directory")
+	}
+}
+
+func TestStatelessFromMountedCookieStore(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "cookies.json")
+	_ = os.WriteFile(secret, []byte(`{"cookies": {"auth": {"value": "token", "source": "curl", "essential": true}}}`), 0600)
+	t.Setenv(CookiesFileEnv, secret)
+
+	stateDir := t.TempDir()
+	client, err := NewWalmartClient(ClientConfig{Stateless: true, CookieDir: stateDir})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if auth := client.CookieStore.Get("auth"); auth == nil || auth.Value != "token" {
+		t.Errorf("Expected mounted cookies to load, got %+v", auth)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "cookies.json")); err != nil {
+		t.Errorf("Expected cookies to persist in the configured state dir: %v", err)
+	}
+}
+
+func TestStatelessBadSecret(t *testing.T) {
+	t.Setenv(CookiesFileEnv, filepath.Join(t.TempDir(), "missing"))
+	if _, err := NewWalmartClient(ClientConfig{Stateless: true}); err == nil {
+		t.Error("Expected error for missing secret file")
+	}
+}
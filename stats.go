@@ -0,0 +1,144 @@
+package walmart
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Operation names used in Stats
+const (
+	OperationGetOrder        = "getOrder"
+	OperationPurchaseHistory = "PurchaseHistoryV2"
+)
+
+const (
+	statsWindow          = 20          // Outcomes kept per operation
+	coolDownMinSamples   = 5           // Outcomes needed before suggesting a cool-down
+	coolDownFailureRate  = 0.5         // Failure rate that triggers a suggestion
+	coolDownBase         = time.Minute // Suggestion after one consecutive failure
+	coolDownMax          = 30 * time.Minute
+	maxCoolDownDoublings = 5
+)
+
+// OperationStats summarizes recent outcomes for one operation
+type OperationStats struct {
+	Requests            int           `json:"requests"`               // Outcomes in the rolling window
+	Failures            int           `json:"failures"`               // Failures in the rolling window
+	FailureRate         float64       `json:"failure_rate"`           // Failures / Requests
+	ConsecutiveFailures int           `json:"consecutive_failures"`   // Failures since the last success
+	LastFailure         *time.Time    `json:"last_failure,omitempty"` // Nil until the first failure
+	CoolDown            time.Duration `json:"cool_down"`              // Suggested pause; zero when healthy
+}
+
+// CoolDownError wraps a request error when the operation is failing often
+// enough that the caller should pause before trying again
+type CoolDownError struct {
+	Operation   string
+	FailureRate float64
+	CoolDown    time.Duration
+	Err         error
+}
+
+func (e *CoolDownError) Error() string {
+	return fmt.Sprintf("%v (%s failing %.0f%% of recent requests, recommend cooling down for %s)",
+		e.Err, e.Operation, e.FailureRate*100, e.CoolDown)
+}
+
+func (e *CoolDownError) Unwrap() error { return e.Err }
+
+// operationTracker keeps a rolling window of outcomes per operation
+type operationTracker struct {
+	mu          sync.Mutex
+	outcomes    map[string][]bool // true for failures
+	consecutive map[string]int
+	lastFailure map[string]time.Time
+}
+
+func (t *operationTracker) record(operation string, failed bool) OperationStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.outcomes == nil {
+		t.outcomes = make(map[string][]bool)
+		t.consecutive = make(map[string]int)
+		t.lastFailure = make(map[string]time.Time)
+	}
+
+	outcomes := append(t.outcomes[operation], failed)
+	if len(outcomes) > statsWindow {
+		outcomes = outcomes[len(outcomes)-statsWindow:]
+	}
+	t.outcomes[operation] = outcomes
+
+	if failed {
+		t.consecutive[operation]++
+		t.lastFailure[operation] = time.Now()
+	} else {
+		t.consecutive[operation] = 0
+	}
+	return t.statsLocked(operation)
+}
+
+func (t *operationTracker) statsLocked(operation string) OperationStats {
+	stats := OperationStats{
+		Requests:            len(t.outcomes[operation]),
+		ConsecutiveFailures: t.consecutive[operation],
+	}
+	if last, ok := t.lastFailure[operation]; ok {
+		stats.LastFailure = &last
+	}
+	for _, failed := range t.outcomes[operation] {
+		if failed {
+			stats.Failures++
+		}
+	}
+	if stats.Requests > 0 {
+		stats.FailureRate = float64(stats.Failures) / float64(stats.Requests)
+	}
+
+	if stats.Requests >= coolDownMinSamples && stats.FailureRate >= coolDownFailureRate && stats.ConsecutiveFailures > 0 {
+		doublings := stats.ConsecutiveFailures - 1
+		if doublings > maxCoolDownDoublings {
+			doublings = maxCoolDownDoublings
+		}
+		stats.CoolDown = coolDownBase << doublings
+		if stats.CoolDown > coolDownMax {
+			stats.CoolDown = coolDownMax
+		}
+	}
+	return stats
+}
+
+// Stats returns rolling success/failure figures per operation
+func (c *WalmartClient) Stats() map[string]OperationStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	stats := make(map[string]OperationStats, len(c.stats.outcomes))
+	for operation := range c.stats.outcomes {
+		stats[operation] = c.stats.statsLocked(operation)
+	}
+	return stats
+}
+
+// recordOutcome tracks err for operation and attaches a cool-down
+// suggestion when the operation has been failing. A missing order is a
+// valid answer, not a failure: GetOrderAutoDetect gets one on every
+// delivery order.
+func (c *WalmartClient) recordOutcome(operation string, err error) error {
+	failed := err != nil && !errors.Is(err, ErrOrderNotFound)
+	stats := c.stats.record(operation, failed)
+	if !failed || stats.CoolDown == 0 {
+		return err
+	}
+	c.logger.Warn("walmart operation keeps failing", "operation", operation,
+		"failure_rate", stats.FailureRate, "cool_down", stats.CoolDown)
+	return &CoolDownError{
+		Operation:   operation,
+		FailureRate: stats.FailureRate,
+		CoolDown:    stats.CoolDown,
+		Err:         err,
+	}
+}
@@ -0,0 +1,88 @@
+package walmart
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestRecordOutcomeSuggestsCoolDown(t *testing.T) {
+	client, _ := NewWalmartClient(ClientConfig{CookieDir: t.TempDir()})
+	failure := &APIError{StatusCode: 403, Kind: ErrSessionExpired}
+
+	for i := 0; i < 2; i++ {
+		_ = client.recordOutcome(OperationGetOrder, nil)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.recordOutcome(OperationGetOrder, failure); err != failure {
+			t.Fatalf("Expected plain error below the sample threshold, got %v", err)
+		}
+	}
+
+	err := client.recordOutcome(OperationGetOrder, failure)
+	var coolDown *CoolDownError
+	if !errors.As(err, &coolDown) {
+		t.Fatalf("Expected CoolDownError, got %v", err)
+	}
+	if coolDown.CoolDown != 4*time.Minute {
+		t.Errorf("Expected 4m cool-down after 3 consecutive failures, got %s", coolDown.CoolDown)
+	}
+	if !errors.Is(err, failure) || !isSessionError(err) {
+		t.Error("CoolDownError should still expose the underlying error")
+	}
+
+	stats := client.Stats()[OperationGetOrder]
+	if stats.Requests != 5 || stats.Failures != 3 || stats.ConsecutiveFailures != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.LastFailure == nil {
+		t.Error("Expected the last failure time to be set")
+	}
+
+	// A success resets the suggestion
+	_ = client.recordOutcome(OperationGetOrder, nil)
+	if stats := client.Stats()[OperationGetOrder]; stats.CoolDown != 0 {
+		t.Errorf("Expected no cool-down after a success, got %s", stats.CoolDown)
+	}
+	if _, ok := client.Stats()[OperationPurchaseHistory]; ok {
+		t.Error("Operations without requests should not appear in stats")
+	}
+}
+
+func TestStatsWindowIsBounded(t *testing.T) {
+	var tracker operationTracker
+	for i := 0; i < statsWindow+10; i++ {
+		tracker.record(OperationPurchaseHistory, i < 10)
+	}
+
+	stats := tracker.record(OperationPurchaseHistory, false)
+	if stats.Requests != statsWindow || stats.Failures != 0 {
+		t.Errorf("Expected old failures to roll off, got %+v", stats)
+	}
+
+	if data, _ := json.Marshal(tracker.record(OperationGetOrder, false)); strings.Contains(string(data), "last_failure") {
+		t.Errorf("Expected last_failure to be omitted without failures, got %s", data)
+	}
+}
+
+func TestAutoDetectMissesAreNotFailures(t *testing.T) {
+	client, srv := newFakeServerClient(t)
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderIsInStore": true}, `{"data":{"order":null}}`)
+	srv.On(server.GetOrderHash, map[string]interface{}{"orderIsInStore": false}, `{"data":{"order":{"id":"DELIVERY1"}}}`)
+
+	for i := 0; i < 2*coolDownMinSamples; i++ {
+		order, err := client.GetOrderAutoDetect("DELIVERY1")
+		if err != nil || order.ID != "DELIVERY1" {
+			t.Fatalf("Lookup %d failed: %v", i, err)
+		}
+	}
+
+	stats := client.Stats()[OperationGetOrder]
+	if stats.Requests != 4*coolDownMinSamples || stats.Failures != 0 || stats.CoolDown != 0 {
+		t.Errorf("Expected in-store misses not to count as failures, got %+v", stats)
+	}
+}
@@ -0,0 +1,271 @@
+package walmart
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CookieStorage persists the cookie store outside the client, e.g. in
+// DynamoDB or S3 for serverless runs. Versions are opaque strings such as an
+// ETag or item revision.
+type CookieStorage interface {
+	// Load returns the stored state and its version, or ErrStateNotFound
+	Load(ctx context.Context) (data []byte, version string, err error)
+	// Save writes data only if the stored version still equals version ("" when
+	// creating), returning the new version or ErrVersionConflict
+	Save(ctx context.Context, data []byte, version string) (newVersion string, err error)
+}
+
+var (
+	// ErrStateNotFound is returned by CookieStorage.Load before the first save
+	ErrStateNotFound = errors.New("cookie state not found")
+	// ErrVersionConflict is returned when another writer saved first
+	ErrVersionConflict = errors.New("cookie state was modified concurrently")
+)
+
+// maxSaveAttempts bounds merge-and-retry rounds on version conflicts
+const maxSaveAttempts = 3
+
+// ExternalState keeps a client's cookies in sync with a CookieStorage
+type ExternalState struct {
+	Client  *WalmartClient
+	Storage CookieStorage
+
+	version  string
+	baseline map[string]string // Cookie values as loaded, to tell local changes apart
+}
+
+// AttachExternalState loads the stored cookies into client, typically at cold start
+func AttachExternalState(ctx context.Context, client *WalmartClient, storage CookieStorage) (*ExternalState, error) {
+	state := &ExternalState{Client: client, Storage: storage}
+
+	data, version, err := storage.Load(ctx)
+	switch {
+	case errors.Is(err, ErrStateNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to load cookie state: %w", err)
+	default:
+		var saved CookieStore
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return nil, fmt.Errorf("invalid cookie state: %w", err)
+		}
+		client.CookieStore.mergeFrom(&saved)
+		state.version = version
+	}
+
+	state.baseline = cookieValues(client.CookieStore)
+	return state, nil
+}
+
+// Flush writes rotated cookies back with a conditional write. When another
+// invocation saved in the meantime, cookies this run changed are merged over
+// the newer state and the write is retried. Read-only storage is left as is.
+func (s *ExternalState) Flush(ctx context.Context) error {
+	for attempt := 0; attempt < maxSaveAttempts; attempt++ {
+		data, err := s.Client.CookieStore.marshal()
+		if err != nil {
+			return err
+		}
+
+		version, err := s.Storage.Save(ctx, data, s.version)
+		if err == nil {
+			s.version = version
+			s.baseline = cookieValues(s.Client.CookieStore)
+			return nil
+		}
+		if errors.Is(err, ErrReadOnlyStorage) {
+			// Rotations only live for this run
+			return nil
+		}
+		if !errors.Is(err, ErrVersionConflict) {
+			return fmt.Errorf("failed to save cookie state: %w", err)
+		}
+
+		if err := s.mergeRemote(ctx); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to save cookie state after %d attempts: %w", maxSaveAttempts, ErrVersionConflict)
+}
+
+// mergeRemote adopts the stored state, keeping cookies this run changed
+func (s *ExternalState) mergeRemote(ctx context.Context) error {
+	data, version, err := s.Storage.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reload cookie state: %w", err)
+	}
+
+	var remote CookieStore
+	if err := json.Unmarshal(data, &remote); err != nil {
+		return fmt.Errorf("invalid cookie state: %w", err)
+	}
+
+	store := s.Client.CookieStore
+	store.mu.Lock()
+	for name, cookie := range remote.Cookies {
+		if _, deleted := store.Deleted[name]; deleted && cookie.Value == s.baseline[name] {
+			continue // Deleted by this run
+		}
+		local, ok := store.Cookies[name]
+		changedLocally := ok && local.Value != s.baseline[name]
+		if !changedLocally {
+			store.Cookies[name] = cookie
+		}
+	}
+	store.mu.Unlock()
+
+	s.version = version
+	s.baseline = cookieValues(&remote)
+	return nil
+}
+
+// RunWithExternalState builds a stateless client around storage, runs fn
+// and writes rotated cookies back, even when fn fails
+func RunWithExternalState(ctx context.Context, storage CookieStorage, config ClientConfig, fn func(*WalmartClient) error) error {
+	config.Stateless = true
+	client, err := NewWalmartClient(config)
+	if err != nil {
+		return err
+	}
+
+	state, err := AttachExternalState(ctx, client, storage)
+	if err != nil {
+		return err
+	}
+
+	runErr := fn(client)
+	if err := state.Flush(ctx); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%w (and %v)", runErr, err)
+		}
+		return err
+	}
+	return runErr
+}
+
+// mergeFrom copies the cookies and fingerprint of a decoded store over cs
+func (cs *CookieStore) mergeFrom(saved *CookieStore) {
+	for name, cookie := range saved.Cookies {
+		cs.Set(name, cookie)
+	}
+	if saved.Fingerprint != nil {
+		cs.mu.Lock()
+		cs.Fingerprint = saved.Fingerprint
+		cs.mu.Unlock()
+	}
+}
+
+func (cs *CookieStore) marshal() ([]byte, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return json.Marshal(cs)
+}
+
+func cookieValues(cs *CookieStore) map[string]string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	values := make(map[string]string, len(cs.Cookies))
+	for name, cookie := range cs.Cookies {
+		values[name] = cookie.Value
+	}
+	return values
+}
+
+// FileStorage is a CookieStorage on a local or mounted filesystem. Versions
+// are content hashes and writes are atomic renames guarded by a lock file.
+type FileStorage struct {
+	Path        string
+	LockTimeout time.Duration // Defaults to 10 seconds
+}
+
+// Load implements CookieStorage
+func (f *FileStorage) Load(ctx context.Context) ([]byte, string, error) {
+	data, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, "", ErrStateNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentVersion(data), nil
+}
+
+// Save implements CookieStorage
+func (f *FileStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	unlock, err := f.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	current := ""
+	if existing, err := os.ReadFile(f.Path); err == nil {
+		current = contentVersion(existing)
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	if current != version {
+		return "", ErrVersionConflict
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".cookies-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return "", err
+	}
+	return contentVersion(data), nil
+}
+
+// lock takes an exclusive lock file next to Path
+func (f *FileStorage) lock(ctx context.Context) (func(), error) {
+	timeout := f.LockTimeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	lockPath := f.Path + ".lock"
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s", lockPath)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func contentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
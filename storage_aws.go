@@ -0,0 +1,97 @@
+package walmart
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// pendingStage labels versions written but not yet made current
+const pendingStage = "walmart-pending"
+
+// AWSSecretsManagerStorage keeps the cookie state in an existing AWS Secrets
+// Manager secret, calling the API directly so it works in Lambda without
+// the aws CLI. A save writes a pending version and then moves AWSCURRENT
+// onto it from the version that was loaded; Secrets Manager refuses the
+// move if AWSCURRENT has moved on, which makes the save a conditional
+// write. Needs secretsmanager:GetSecretValue, PutSecretValue and
+// UpdateSecretVersionStage.
+type AWSSecretsManagerStorage struct {
+	SecretID    string
+	Region      string          // Defaults to AWS_REGION
+	Credentials *AWSCredentials // Defaults to AWSCredentialsFromEnv
+	HTTPClient  *http.Client
+	Endpoint    string // Overrides the regional endpoint, e.g. for tests
+}
+
+func (s *AWSSecretsManagerStorage) call(ctx context.Context, target string, input, output interface{}) error {
+	cfg := awsConfig{Region: s.Region, Credentials: s.Credentials, HTTPClient: s.HTTPClient, Endpoint: s.Endpoint}
+	return awsJSON(ctx, cfg, "secretsmanager", "1.1", "secretsmanager."+target, input, output)
+}
+
+// Load implements CookieStorage
+func (s *AWSSecretsManagerStorage) Load(ctx context.Context) ([]byte, string, error) {
+	var out struct {
+		SecretString string `json:"SecretString"`
+		VersionID    string `json:"VersionId"`
+	}
+	err := s.call(ctx, "GetSecretValue", map[string]string{"SecretId": s.SecretID}, &out)
+	if isAWSError(err, "ResourceNotFoundException") {
+		return nil, "", ErrStateNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return []byte(out.SecretString), out.VersionID, nil
+}
+
+// Save implements CookieStorage
+func (s *AWSSecretsManagerStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	var put struct {
+		VersionID string `json:"VersionId"`
+	}
+	err = s.call(ctx, "PutSecretValue", map[string]interface{}{
+		"SecretId":           s.SecretID,
+		"SecretString":       string(data),
+		"ClientRequestToken": token,
+		"VersionStages":      []string{pendingStage},
+	}, &put)
+	if err != nil {
+		return "", fmt.Errorf("failed to write secret version: %w", err)
+	}
+
+	move := map[string]string{
+		"SecretId":        s.SecretID,
+		"VersionStage":    "AWSCURRENT",
+		"MoveToVersionId": put.VersionID,
+	}
+	if version != "" {
+		move["RemoveFromVersionId"] = version
+	}
+	err = s.call(ctx, "UpdateSecretVersionStage", move, nil)
+	if isAWSError(err, "InvalidParameterException") {
+		// AWSCURRENT is no longer on the version we loaded
+		return "", ErrVersionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to promote secret version: %w", err)
+	}
+	return put.VersionID, nil
+}
+
+// randomToken is an idempotency token for AWS requests
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New("failed to generate request token")
+	}
+	return hex.EncodeToString(b), nil
+}
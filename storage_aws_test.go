@@ -0,0 +1,98 @@
+package walmart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSecretsManager implements the version stage semantics the storage
+// relies on
+type fakeSecretsManager struct {
+	mu       sync.Mutex
+	values   map[string]string
+	current  string
+	sequence int
+}
+
+func (f *fakeSecretsManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	var in map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+
+	fail := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"__type": code, "message": code})
+	}
+	switch r.Header.Get("X-Amz-Target") {
+	case "secretsmanager.GetSecretValue":
+		if f.current == "" {
+			fail("ResourceNotFoundException")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": f.values[f.current], "VersionId": f.current})
+	case "secretsmanager.PutSecretValue":
+		f.sequence++
+		id := in["ClientRequestToken"].(string)
+		f.values[id] = in["SecretString"].(string)
+		_ = json.NewEncoder(w).Encode(map[string]string{"VersionId": id})
+	case "secretsmanager.UpdateSecretVersionStage":
+		from, _ := in["RemoveFromVersionId"].(string)
+		if f.current != "" && from != f.current {
+			fail("InvalidParameterException")
+			return
+		}
+		f.current = in["MoveToVersionId"].(string)
+		_, _ = w.Write([]byte("{}"))
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestAWSSecretsManagerStorage(t *testing.T) {
+	fake := &fakeSecretsManager{values: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	storage := &AWSSecretsManagerStorage{
+		SecretID:    "walmart",
+		Region:      "us-east-1",
+		Credentials: &AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		Endpoint:    srv.URL,
+	}
+	ctx := context.Background()
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+	v1, err := storage.Save(ctx, []byte(`{"a":1}`), "")
+	if err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	data, version, err := storage.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` || version != v1 {
+		t.Fatalf("Unexpected load: %s %s %v", data, version, err)
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{"a":2}`), v1); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+	// A run still holding v1 must not overwrite the newer state
+	if _, err := storage.Save(ctx, []byte(`{"a":3}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+	if data, _, _ := storage.Load(ctx); string(data) != `{"a":2}` {
+		t.Errorf("Expected the second save to stay current, got %s", data)
+	}
+}
@@ -0,0 +1,83 @@
+package walmart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrReadOnlyStorage is returned by Save on storage without a save command
+var ErrReadOnlyStorage = errors.New("cookie storage is read-only")
+
+// commandConflictStatus is the exit status a save command uses to report
+// that the stored state changed
+const commandConflictStatus = 3
+
+// CommandStorage is a CookieStorage backed by external commands, e.g. a
+// secrets manager CLI, so session material never touches the local disk.
+// Load prints the state on stdout and Save reads it from stdin. Versions are
+// hex SHA-256 hashes of the state without surrounding whitespace. Save runs
+// with the version it expects in $WALMART_STATE_VERSION, empty when creating;
+// the command must compare it with the stored state as part of its write and
+// exit with status 3 on a mismatch. Commands that can't write conditionally
+// offer no protection against concurrent runs.
+type CommandStorage struct {
+	LoadCommand []string
+	SaveCommand []string // Nil makes the storage read-only
+}
+
+// Load implements CookieStorage
+func (s *CommandStorage) Load(ctx context.Context) ([]byte, string, error) {
+	out, err := s.run(ctx, s.LoadCommand, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	data := bytes.TrimSpace(out)
+	if len(data) == 0 {
+		return nil, "", ErrStateNotFound
+	}
+	return data, contentVersion(data), nil
+}
+
+// Save implements CookieStorage
+func (s *CommandStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	if len(s.SaveCommand) == 0 {
+		return "", ErrReadOnlyStorage
+	}
+
+	_, err := s.run(ctx, s.SaveCommand, data, "WALMART_STATE_VERSION="+version)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == commandConflictStatus {
+		return "", ErrVersionConflict
+	}
+	if err != nil {
+		return "", err
+	}
+	return contentVersion(data), nil
+}
+
+func (s *CommandStorage) run(ctx context.Context, command []string, stdin []byte, env ...string) ([]byte, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("no command configured")
+	}
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // commands come from the caller's configuration
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
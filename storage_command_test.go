@@ -0,0 +1,73 @@
+package walmart
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandStorage(t *testing.T) {
+	for _, tool := range []string{"sh", "sha256sum"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skip(tool + " not available")
+		}
+	}
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "secret")
+	storage := &CommandStorage{
+		LoadCommand: []string{"sh", "-c", `cat "$0" 2>/dev/null || true`, path},
+		// A conditional write: compare the stored hash, then replace
+		SaveCommand: []string{"sh", "-c", `current=$([ -s "$0" ] && sha256sum < "$0" | cut -d" " -f1)
+[ "$current" = "$WALMART_STATE_VERSION" ] || exit 3
+cat > "$0"`, path},
+	}
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound for empty secret, got %v", err)
+	}
+
+	v1, err := storage.Save(ctx, []byte(`{"cookies":{"CID":{"value":"a"}}}`), "")
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, version, err := storage.Load(ctx)
+	if err != nil || version != v1 || string(data) != `{"cookies":{"CID":{"value":"a"}}}` {
+		t.Errorf("Unexpected load: %s %s %v", data, version, err)
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{}`), "stale"); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected conflict for stale version, got %v", err)
+	}
+
+	readOnly := &CommandStorage{LoadCommand: storage.LoadCommand}
+	if _, err := readOnly.Save(ctx, []byte(`{}`), v1); !errors.Is(err, ErrReadOnlyStorage) {
+		t.Errorf("Expected ErrReadOnlyStorage, got %v", err)
+	}
+
+	err = RunWithExternalState(ctx, readOnly, ClientConfig{}, func(client *WalmartClient) error {
+		if cid := client.CookieStore.Get("CID"); cid == nil || cid.Value != "a" {
+			t.Errorf("Expected cookies from the secret, got %+v", cid)
+		}
+		client.CookieStore.Set("CID", &Cookie{Value: "rotated"})
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Read-only storage should not fail the run, got %v", err)
+	}
+}
+
+func TestCommandStorageReportsFailures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	storage := &CommandStorage{LoadCommand: []string{"sh", "-c", "echo denied >&2; exit 3"}}
+	_, _, err := storage.Load(context.Background())
+	if err == nil || !contains(err.Error(), "denied") {
+		t.Errorf("Expected command stderr in error, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package walmart
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DynamoDBStorage keeps the cookie state in one DynamoDB item. Each save
+// writes a new random version attribute with a condition on the version
+// that was loaded, so concurrent invocations can't overwrite each other.
+// The table needs a string partition key; needs dynamodb:GetItem and
+// dynamodb:PutItem.
+type DynamoDBStorage struct {
+	Table       string
+	KeyName     string          // Partition key attribute; defaults to "id"
+	KeyValue    string          // Partition key of the item; defaults to "walmart"
+	Region      string          // Defaults to AWS_REGION
+	Credentials *AWSCredentials // Defaults to AWSCredentialsFromEnv
+	HTTPClient  *http.Client
+	Endpoint    string // Overrides the regional endpoint, e.g. for DynamoDB Local
+}
+
+type dynamoString struct {
+	S string `json:"S"`
+}
+
+func (s *DynamoDBStorage) call(ctx context.Context, target string, input, output interface{}) error {
+	cfg := awsConfig{Region: s.Region, Credentials: s.Credentials, HTTPClient: s.HTTPClient, Endpoint: s.Endpoint}
+	return awsJSON(ctx, cfg, "dynamodb", "1.0", "DynamoDB_20120810."+target, input, output)
+}
+
+func (s *DynamoDBStorage) key() map[string]dynamoString {
+	name, value := s.KeyName, s.KeyValue
+	if name == "" {
+		name = "id"
+	}
+	if value == "" {
+		value = "walmart"
+	}
+	return map[string]dynamoString{name: {S: value}}
+}
+
+// Load implements CookieStorage
+func (s *DynamoDBStorage) Load(ctx context.Context) ([]byte, string, error) {
+	var out struct {
+		Item map[string]dynamoString `json:"Item"`
+	}
+	err := s.call(ctx, "GetItem", map[string]interface{}{
+		"TableName":      s.Table,
+		"Key":            s.key(),
+		"ConsistentRead": true,
+	}, &out)
+	if err != nil {
+		return nil, "", err
+	}
+	if out.Item == nil {
+		return nil, "", ErrStateNotFound
+	}
+	return []byte(out.Item["state"].S), out.Item["version"].S, nil
+}
+
+// Save implements CookieStorage
+func (s *DynamoDBStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	next, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	item := s.key()
+	item["state"] = dynamoString{S: string(data)}
+	item["version"] = dynamoString{S: next}
+	input := map[string]interface{}{
+		"TableName":                s.Table,
+		"Item":                     item,
+		"ExpressionAttributeNames": map[string]string{"#v": "version"},
+	}
+	if version == "" {
+		input["ConditionExpression"] = "attribute_not_exists(#v)"
+	} else {
+		input["ConditionExpression"] = "#v = :v"
+		input["ExpressionAttributeValues"] = map[string]dynamoString{":v": {S: version}}
+	}
+
+	err = s.call(ctx, "PutItem", input, nil)
+	if isAWSError(err, "ConditionalCheckFailedException") {
+		return "", ErrVersionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write DynamoDB item: %w", err)
+	}
+	return next, nil
+}
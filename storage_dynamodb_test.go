@@ -0,0 +1,75 @@
+package walmart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDynamoDBStorage(t *testing.T) {
+	var mu sync.Mutex
+	var item map[string]dynamoString
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var in struct {
+			Key                       map[string]dynamoString `json:"Key"`
+			Item                      map[string]dynamoString `json:"Item"`
+			ConditionExpression       string                  `json:"ConditionExpression"`
+			ExpressionAttributeValues map[string]dynamoString `json:"ExpressionAttributeValues"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.GetItem":
+			if in.Key["pk"].S != "home" {
+				t.Errorf("Unexpected key: %v", in.Key)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"Item": item})
+		case "DynamoDB_20120810.PutItem":
+			ok := (in.ConditionExpression == "attribute_not_exists(#v)" && item == nil) ||
+				(in.ConditionExpression == "#v = :v" && item != nil && item["version"] == in.ExpressionAttributeValues[":v"])
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+				return
+			}
+			item = in.Item
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer srv.Close()
+
+	storage := &DynamoDBStorage{
+		Table:       "walmart",
+		KeyName:     "pk",
+		KeyValue:    "home",
+		Region:      "us-east-1",
+		Credentials: &AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		Endpoint:    srv.URL,
+	}
+	ctx := context.Background()
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+	v1, err := storage.Save(ctx, []byte(`{"a":1}`), "")
+	if err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	data, version, err := storage.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` || version != v1 {
+		t.Fatalf("Unexpected load: %s %s %v", data, version, err)
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{"a":2}`), v1); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"a":3}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
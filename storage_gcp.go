@@ -0,0 +1,225 @@
+package walmart
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	gcpMetadataTokenURL      = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// gcpVersionAnnotation names the secret version holding the current state
+	gcpVersionAnnotation = "walmart-cookie-version"
+)
+
+// GCPSecretManagerStorage keeps the cookie state in an existing GCP Secret
+// Manager secret through the REST API. Each save adds a version and then
+// points an annotation on the secret at it, sending the etag that was
+// loaded; Secret Manager rejects the update if the secret changed since,
+// which makes the save a conditional write. Needs
+// secretmanager.versions.access, secretmanager.versions.add and
+// secretmanager.secrets.update.
+type GCPSecretManagerStorage struct {
+	Project string
+	Secret  string
+
+	// Token returns an OAuth access token; defaults to the metadata server
+	// of Cloud Run, Cloud Functions and GCE
+	Token      func(ctx context.Context) (string, error)
+	HTTPClient *http.Client
+	Endpoint   string // Overrides the API endpoint, e.g. for tests
+}
+
+// GCPError is an error response from a Google Cloud API
+type GCPError struct {
+	StatusCode int
+	Status     string // e.g. "NOT_FOUND", "ABORTED"
+	Message    string
+}
+
+func (e *GCPError) Error() string {
+	return fmt.Sprintf("GCP %s (HTTP %d): %s", e.Status, e.StatusCode, e.Message)
+}
+
+type gcpSecret struct {
+	Etag        string            `json:"etag"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Load implements CookieStorage. The version is the secret's etag.
+func (s *GCPSecretManagerStorage) Load(ctx context.Context) ([]byte, string, error) {
+	secret, err := s.secret(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	current := secret.Annotations[gcpVersionAnnotation]
+	if current == "" {
+		return nil, "", ErrStateNotFound
+	}
+
+	var access struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := s.call(ctx, "GET", "/versions/"+current+":access", nil, &access); err != nil {
+		return nil, "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(access.Payload.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid secret payload: %w", err)
+	}
+	return data, secret.Etag, nil
+}
+
+// Save implements CookieStorage
+func (s *GCPSecretManagerStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	secret, err := s.secret(ctx)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		if secret.Annotations[gcpVersionAnnotation] != "" {
+			return "", ErrVersionConflict
+		}
+		version = secret.Etag
+	} else if secret.Etag != version {
+		return "", ErrVersionConflict
+	}
+
+	var added struct {
+		Name string `json:"name"` // projects/<p>/secrets/<s>/versions/<n>
+	}
+	payload := map[string]interface{}{"payload": map[string]string{"data": base64.StdEncoding.EncodeToString(data)}}
+	if err := s.call(ctx, "POST", ":addVersion", payload, &added); err != nil {
+		return "", fmt.Errorf("failed to add secret version: %w", err)
+	}
+	number := added.Name[strings.LastIndex(added.Name, "/")+1:]
+
+	annotations := make(map[string]string, len(secret.Annotations)+1)
+	for k, v := range secret.Annotations {
+		annotations[k] = v
+	}
+	annotations[gcpVersionAnnotation] = number
+
+	var updated gcpSecret
+	err = s.call(ctx, "PATCH", "?updateMask=annotations", gcpSecret{Etag: version, Annotations: annotations}, &updated)
+	var apiErr *GCPError
+	if errors.As(err, &apiErr) && (apiErr.Status == "ABORTED" || apiErr.Status == "FAILED_PRECONDITION") {
+		// Someone else saved first; our version was never current
+		_ = s.call(ctx, "POST", "/versions/"+number+":destroy", map[string]string{}, nil)
+		return "", ErrVersionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to update secret: %w", err)
+	}
+	return updated.Etag, nil
+}
+
+func (s *GCPSecretManagerStorage) secret(ctx context.Context) (*gcpSecret, error) {
+	var secret gcpSecret
+	if err := s.call(ctx, "GET", "", nil, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// call sends a request relative to the secret's resource URL
+func (s *GCPSecretManagerStorage) call(ctx context.Context, method, suffix string, input, output interface{}) error {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = gcpSecretManagerEndpoint
+	}
+	resource := fmt.Sprintf("%s/v1/projects/%s/secrets/%s%s", endpoint, s.Project, s.Secret, suffix)
+
+	var body io.Reader
+	if input != nil {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, resource, body)
+	if err != nil {
+		return err
+	}
+
+	tokenFunc := s.Token
+	if tokenFunc == nil {
+		tokenFunc = s.metadataToken
+	}
+	token, err := tokenFunc(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get GCP access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var payload struct {
+			Error struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(respBody, &payload)
+		return &GCPError{StatusCode: resp.StatusCode, Status: payload.Error.Status, Message: payload.Error.Message}
+	}
+	if output == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, output)
+}
+
+// metadataToken fetches the service account token from the metadata server
+func (s *GCPSecretManagerStorage) metadataToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned HTTP %d", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+func (s *GCPSecretManagerStorage) client() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
@@ -0,0 +1,143 @@
+package walmart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGCPSecret serves one secret with etag-checked annotation updates
+type fakeGCPSecret struct {
+	mu          sync.Mutex
+	etag        int
+	annotations map[string]string
+	versions    map[string]string
+	destroyed   []string
+}
+
+func (f *fakeGCPSecret) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	const prefix = "/v1/projects/p/secrets/s"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	secret := func() map[string]interface{} {
+		return map[string]interface{}{"etag": fmt.Sprintf(`"%d"`, f.etag), "annotations": f.annotations}
+	}
+	switch {
+	case r.Method == "GET" && path == "":
+		_ = json.NewEncoder(w).Encode(secret())
+	case r.Method == "GET" && strings.HasSuffix(path, ":access"):
+		number := strings.TrimSuffix(strings.TrimPrefix(path, "/versions/"), ":access")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"payload": map[string]string{"data": f.versions[number]}})
+	case r.Method == "POST" && path == ":addVersion":
+		var in struct {
+			Payload struct {
+				Data string `json:"data"`
+			} `json:"payload"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		number := fmt.Sprint(len(f.versions) + 1)
+		f.versions[number] = in.Payload.Data
+		_ = json.NewEncoder(w).Encode(map[string]string{"name": "projects/p/secrets/s/versions/" + number})
+	case r.Method == "POST" && strings.HasSuffix(path, ":destroy"):
+		f.destroyed = append(f.destroyed, strings.TrimSuffix(strings.TrimPrefix(path, "/versions/"), ":destroy"))
+		_, _ = w.Write([]byte("{}"))
+	case r.Method == "PATCH" && path == "" && r.URL.Query().Get("updateMask") == "annotations":
+		var in gcpSecret
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in.Etag != fmt.Sprintf(`"%d"`, f.etag) {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"status":"ABORTED","message":"etag mismatch"}}`))
+			return
+		}
+		f.etag++
+		f.annotations = in.Annotations
+		_ = json.NewEncoder(w).Encode(secret())
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"status":"NOT_FOUND","message":"not found"}}`))
+	}
+}
+
+func TestGCPSecretManagerStorage(t *testing.T) {
+	fake := &fakeGCPSecret{versions: map[string]string{}, annotations: map[string]string{"team": "home"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	storage := &GCPSecretManagerStorage{
+		Project:  "p",
+		Secret:   "s",
+		Token:    func(context.Context) (string, error) { return "token", nil },
+		Endpoint: srv.URL,
+	}
+	ctx := context.Background()
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+	v1, err := storage.Save(ctx, []byte(`{"a":1}`), "")
+	if err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	data, version, err := storage.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` || version != v1 {
+		t.Fatalf("Unexpected load: %s %s %v", data, version, err)
+	}
+	if fake.annotations["team"] != "home" {
+		t.Error("Expected other annotations to be kept")
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{"a":2}`), v1); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"a":3}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+	if data, _, _ := storage.Load(ctx); string(data) != `{"a":2}` {
+		t.Errorf("Expected the second save to stay current, got %s", data)
+	}
+}
+
+func TestGCPSecretManagerStorageLosesRace(t *testing.T) {
+	fake := &fakeGCPSecret{versions: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	storage := &GCPSecretManagerStorage{
+		Project:  "p",
+		Secret:   "s",
+		Token:    func(context.Context) (string, error) { return "token", nil },
+		Endpoint: srv.URL,
+	}
+	// Another writer updates the secret between our read and our patch
+	storage.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, ":addVersion") {
+			fake.mu.Lock()
+			fake.etag++
+			fake.mu.Unlock()
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	if _, err := storage.Save(context.Background(), []byte(`{}`), ""); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+	if len(fake.destroyed) != 1 {
+		t.Errorf("Expected the orphaned version to be destroyed, got %v", fake.destroyed)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
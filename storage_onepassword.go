@@ -0,0 +1,149 @@
+package walmart
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOnePasswordField is the item field holding the cookie state
+const defaultOnePasswordField = "cookies"
+
+// OnePasswordConnectStorage keeps the cookie state in a field of a
+// 1Password item through a 1Password Connect server's REST API, so the
+// secret never appears in a process list. Versions are the item version.
+// Connect has no conditional write, so Save checks the version just before
+// replacing the item and a writer in between can still be overwritten; use
+// one of the cloud storages when runs overlap.
+type OnePasswordConnectStorage struct {
+	Host       string // Connect server, e.g. http://localhost:8080
+	Token      string // Connect access token
+	Vault      string // Vault ID
+	Item       string // Item ID
+	Field      string // Label of the field holding the state; defaults to "cookies"
+	HTTPClient *http.Client
+}
+
+// onePasswordItem keeps the raw item so replacing it preserves every field
+type onePasswordItem map[string]interface{}
+
+func (item onePasswordItem) version() string {
+	return fmt.Sprint(item["version"])
+}
+
+// field finds the field with the given label or ID
+func (item onePasswordItem) field(name string) map[string]interface{} {
+	fields, _ := item["fields"].([]interface{})
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if ok && (field["label"] == name || field["id"] == name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// Load implements CookieStorage
+func (s *OnePasswordConnectStorage) Load(ctx context.Context) ([]byte, string, error) {
+	item, err := s.item(ctx, "GET", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	field := item.field(s.fieldName())
+	value, _ := field["value"].(string)
+	if value == "" {
+		return nil, "", ErrStateNotFound
+	}
+	return []byte(value), item.version(), nil
+}
+
+// Save implements CookieStorage
+func (s *OnePasswordConnectStorage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	item, err := s.item(ctx, "GET", nil)
+	if err != nil {
+		return "", err
+	}
+	field := item.field(s.fieldName())
+	current, _ := field["value"].(string)
+	if (version == "" && current != "") || (version != "" && item.version() != version) {
+		return "", ErrVersionConflict
+	}
+
+	if field == nil {
+		fields, _ := item["fields"].([]interface{})
+		item["fields"] = append(fields, map[string]interface{}{
+			"id":    s.fieldName(),
+			"label": s.fieldName(),
+			"type":  "CONCEALED",
+			"value": string(data),
+		})
+	} else {
+		field["value"] = string(data)
+	}
+
+	updated, err := s.item(ctx, "PUT", item)
+	if err != nil {
+		return "", fmt.Errorf("failed to update 1Password item: %w", err)
+	}
+	return updated.version(), nil
+}
+
+func (s *OnePasswordConnectStorage) fieldName() string {
+	if s.Field != "" {
+		return s.Field
+	}
+	return defaultOnePasswordField
+}
+
+func (s *OnePasswordConnectStorage) item(ctx context.Context, method string, item onePasswordItem) (onePasswordItem, error) {
+	var body io.Reader
+	if item != nil {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	resource := fmt.Sprintf("%s/v1/vaults/%s/items/%s", strings.TrimSuffix(s.Host, "/"), s.Vault, s.Item)
+	req, err := http.NewRequestWithContext(ctx, method, resource, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return nil, ErrVersionConflict
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("1Password Connect returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result onePasswordItem
+	decoder := json.NewDecoder(bytes.NewReader(respBody))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid 1Password item: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package walmart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOnePasswordConnectStorage(t *testing.T) {
+	var mu sync.Mutex
+	item := map[string]interface{}{
+		"id":      "item",
+		"version": 1,
+		"fields":  []interface{}{map[string]interface{}{"id": "username", "label": "username", "value": "me"}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Path != "/v1/vaults/vault/items/item" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "PUT" {
+			var updated map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			version, _ := updated["version"].(float64)
+			updated["version"] = int(version) + 1
+			item = updated
+		}
+		_ = json.NewEncoder(w).Encode(item)
+	}))
+	defer srv.Close()
+
+	storage := &OnePasswordConnectStorage{Host: srv.URL, Token: "token", Vault: "vault", Item: "item"}
+	ctx := context.Background()
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+	v1, err := storage.Save(ctx, []byte(`{"a":1}`), "")
+	if err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	data, version, err := storage.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` || version != v1 || v1 != "2" {
+		t.Fatalf("Unexpected load: %s %s %v", data, version, err)
+	}
+	if fields := item["fields"].([]interface{}); len(fields) != 2 {
+		t.Errorf("Expected the existing field to be kept, got %v", fields)
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{"a":2}`), v1); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"a":3}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package walmart
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// S3Storage keeps the cookie state in one S3 object. Versions are the
+// object's ETag and saves are conditional writes: If-Match against the
+// loaded ETag, or If-None-Match: * when there was no object yet. Needs
+// s3:GetObject and s3:PutObject.
+type S3Storage struct {
+	Bucket      string
+	Key         string
+	Region      string          // Defaults to AWS_REGION
+	Credentials *AWSCredentials // Defaults to AWSCredentialsFromEnv
+	HTTPClient  *http.Client
+	Endpoint    string // Addresses the bucket path-style on this endpoint, e.g. for MinIO or tests
+}
+
+// Load implements CookieStorage
+func (s *S3Storage) Load(ctx context.Context) ([]byte, string, error) {
+	resp, body, err := s.do(ctx, "GET", nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, resp.Header.Get("ETag"), nil
+	case http.StatusNotFound:
+		return nil, "", ErrStateNotFound
+	default:
+		return nil, "", parseS3Error(resp.StatusCode, body)
+	}
+}
+
+// Save implements CookieStorage
+func (s *S3Storage) Save(ctx context.Context, data []byte, version string) (string, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if version == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", version)
+	}
+
+	resp, body, err := s.do(ctx, "PUT", data, header)
+	if err != nil {
+		return "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// 409 means a concurrent conditional write to the same key won
+		return "", ErrVersionConflict
+	default:
+		return "", fmt.Errorf("failed to write S3 object: %w", parseS3Error(resp.StatusCode, body))
+	}
+}
+
+func (s *S3Storage) do(ctx context.Context, method string, data []byte, header http.Header) (*http.Response, []byte, error) {
+	cfg := awsConfig{Region: s.Region, Credentials: s.Credentials, HTTPClient: s.HTTPClient}
+	region, creds, client, err := cfg.resolve()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := strings.Split(strings.TrimPrefix(s.Key, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	object := strings.Join(segments, "/")
+	resource := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, region, object)
+	if s.Endpoint != "" {
+		resource = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.Endpoint, "/"), s.Bucket, object)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, resource, bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	signAWSRequest(req, data, creds, region, "s3", time.Now())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// parseS3Error reads S3's XML error responses
+func parseS3Error(status int, body []byte) *AWSError {
+	var payload struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.Unmarshal(body, &payload)
+	return &AWSError{StatusCode: status, Code: payload.Code, Message: payload.Message}
+}
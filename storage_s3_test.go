@@ -0,0 +1,78 @@
+package walmart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestS3Storage(t *testing.T) {
+	var mu sync.Mutex
+	var object []byte
+	var etag string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.EscapedPath() != "/bucket/walmart/cookies%20state.json" || r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			if object == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+				return
+			}
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write(object)
+		case "PUT":
+			if (r.Header.Get("If-None-Match") == "*" && object != nil) ||
+				(r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`<Error><Code>PreconditionFailed</Code></Error>`))
+				return
+			}
+			object, _ = io.ReadAll(r.Body)
+			etag = fmt.Sprintf(`"%d"`, len(etag)+1)
+			w.Header().Set("ETag", etag)
+		}
+	}))
+	defer srv.Close()
+
+	storage := &S3Storage{
+		Bucket:      "bucket",
+		Key:         "walmart/cookies state.json",
+		Region:      "us-east-1",
+		Credentials: &AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		Endpoint:    srv.URL,
+	}
+	ctx := context.Background()
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+	v1, err := storage.Save(ctx, []byte(`{"a":1}`), "")
+	if err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"a":1}`), ""); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected a create over an existing object to conflict, got %v", err)
+	}
+	data, version, err := storage.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` || version != v1 {
+		t.Fatalf("Unexpected load: %s %s %v", data, version, err)
+	}
+
+	if _, err := storage.Save(ctx, []byte(`{"a":2}`), v1); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"a":3}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package walmart
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStorageConditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	storage := &FileStorage{Path: filepath.Join(t.TempDir(), "state.json")}
+
+	if _, _, err := storage.Load(ctx); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+
+	v1, err := storage.Save(ctx, []byte(`{"cookies":{}}`), "")
+	if err != nil {
+		t.Fatalf("Initial save failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"cookies":{"a":{}}}`), ""); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected conflict when creating over existing state, got %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{"cookies":{"b":{}}}`), v1); err != nil {
+		t.Errorf("Save with current version failed: %v", err)
+	}
+	if _, err := storage.Save(ctx, []byte(`{}`), v1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected conflict for stale version, got %v", err)
+	}
+}
+
+func TestExternalStateMergesConcurrentRuns(t *testing.T) {
+	ctx := context.Background()
+	storage := &FileStorage{Path: filepath.Join(t.TempDir(), "state.json")}
+	_, _ = storage.Save(ctx, []byte(`{"cookies":{"CID":{"value":"cid0"},"bstc":{"value":"b0"},"xptwg":{"value":"x0"}}}`), "")
+
+	newClient := func() *WalmartClient {
+		client, _ := NewWalmartClient(ClientConfig{Stateless: true})
+		return client
+	}
+
+	// Two invocations start from the same state
+	first, _ := AttachExternalState(ctx, newClient(), storage)
+	second, _ := AttachExternalState(ctx, newClient(), storage)
+
+	first.Client.CookieStore.Set("bstc", &Cookie{Value: "b1"})
+	if err := first.Flush(ctx); err != nil {
+		t.Fatalf("First flush failed: %v", err)
+	}
+
+	second.Client.CookieStore.Set("xptwg", &Cookie{Value: "x2"})
+	if err := second.Flush(ctx); err != nil {
+		t.Fatalf("Second flush should merge and retry, got %v", err)
+	}
+
+	check, _ := AttachExternalState(ctx, newClient(), storage)
+	store := check.Client.CookieStore
+	if store.Get("bstc").Value != "b1" || store.Get("xptwg").Value != "x2" || store.Get("CID").Value != "cid0" {
+		t.Errorf("Expected both rotations to survive, got bstc=%s xptwg=%s CID=%s",
+			store.Get("bstc").Value, store.Get("xptwg").Value, store.Get("CID").Value)
+	}
+}
+
+func TestRunWithExternalState(t *testing.T) {
+	ctx := context.Background()
+	storage := &FileStorage{Path: filepath.Join(t.TempDir(), "state.json")}
+
+	runErr := errors.New("sync failed")
+	err := RunWithExternalState(ctx, storage, ClientConfig{}, func(client *WalmartClient) error {
+		client.CookieStore.Set("CID", &Cookie{Value: "rotated"})
+		return runErr
+	})
+	if !errors.Is(err, runErr) {
+		t.Fatalf("Expected run error to be returned, got %v", err)
+	}
+
+	// Rotations are written back even when the run fails
+	data, _, err := storage.Load(ctx)
+	if err != nil || !contains(string(data), "rotated") {
+		t.Errorf("Expected rotated cookie to be saved, got %s (%v)", data, err)
+	}
+}
@@ -0,0 +1,79 @@
+package walmart
+
+import "strings"
+
+// TenderKind categorizes how an order was paid
+type TenderKind string
+
+const (
+	TenderCard      TenderKind = "card"      // Credit or debit card
+	TenderGiftCard  TenderKind = "gift_card" // Walmart gift card balance
+	TenderFinancing TenderKind = "financing" // Paid over time through a financing provider
+	TenderWallet    TenderKind = "wallet"    // Paid through a wallet service such as PayPal or Venmo
+	TenderOther     TenderKind = "other"     // Unrecognized tender
+)
+
+// tenderRule matches payment types and descriptions, checked in order
+type tenderRule struct {
+	kind     TenderKind
+	provider string
+	keywords []string
+}
+
+var tenderRules = []tenderRule{
+	{TenderFinancing, "Affirm", []string{"affirm"}},
+	{TenderFinancing, "Klarna", []string{"klarna"}},
+	// Venmo payments can be labelled as PayPal, so check it first
+	{TenderWallet, "Venmo", []string{"venmo"}},
+	{TenderWallet, "PayPal", []string{"paypal", "pay pal"}},
+	{TenderGiftCard, "", []string{"gift card", "giftcard"}},
+	{TenderCard, "", []string{"creditcard", "credit card", "debitcard", "debit card", "visa", "mastercard", "amex", "american express", "discover"}},
+}
+
+// matchTender classifies the combined payment fields
+func matchTender(fields ...string) tenderRule {
+	text := strings.ToLower(strings.Join(fields, " "))
+	for _, rule := range tenderRules {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(text, keyword) {
+				return rule
+			}
+		}
+	}
+	return tenderRule{kind: TenderOther}
+}
+
+// Tender returns how this payment method was paid
+func (m OrderPaymentMethod) Tender() TenderKind {
+	return matchTender(m.PaymentType, m.CardType, m.Description).kind
+}
+
+// Provider names the financing or wallet service behind the tender, or ""
+// for plain cards. Bank matching should target this provider's transactions
+// rather than a card charge for the order total.
+func (m OrderPaymentMethod) Provider() string {
+	return matchTender(m.PaymentType, m.CardType, m.Description).provider
+}
+
+// Tender returns how this group payment was paid
+func (m PaymentMethod) Tender() TenderKind {
+	return matchTender(m.DisplayName).kind
+}
+
+// Provider names the financing or wallet service behind the tender, or ""
+func (m PaymentMethod) Provider() string {
+	return matchTender(m.DisplayName).provider
+}
+
+// IsFinanced reports whether any part of the order was paid through financing
+func (o *Order) IsFinanced() bool {
+	if o == nil {
+		return false
+	}
+	for _, method := range o.PaymentMethods {
+		if method.Tender() == TenderFinancing {
+			return true
+		}
+	}
+	return false
+}
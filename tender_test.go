@@ -0,0 +1,35 @@
+package walmart
+
+import "testing"
+
+func TestPaymentMethodTender(t *testing.T) {
+	tests := []struct {
+		method   OrderPaymentMethod
+		tender   TenderKind
+		provider string
+	}{
+		{OrderPaymentMethod{Description: "Visa ending in 0000", CardType: "VISA", PaymentType: "CREDITCARD"}, TenderCard, ""},
+		{OrderPaymentMethod{Description: "Walmart Gift Card", PaymentType: "GIFTCARD"}, TenderGiftCard, ""},
+		{OrderPaymentMethod{Description: "Affirm", PaymentType: "AFFIRM"}, TenderFinancing, "Affirm"},
+		{OrderPaymentMethod{Description: "PayPal", PaymentType: "PAYPAL"}, TenderWallet, "PayPal"},
+		{OrderPaymentMethod{Description: "Venmo", PaymentType: "PAYPAL"}, TenderWallet, "Venmo"},
+		{OrderPaymentMethod{Description: "Something new"}, TenderOther, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.method.Tender(); got != tt.tender {
+			t.Errorf("%s: expected %s, got %s", tt.method.Description, tt.tender, got)
+		}
+		if got := tt.method.Provider(); got != tt.provider {
+			t.Errorf("%s: expected provider %q, got %q", tt.method.Description, tt.provider, got)
+		}
+	}
+
+	order := &Order{PaymentMethods: []OrderPaymentMethod{tests[0].method, tests[2].method}}
+	if !order.IsFinanced() {
+		t.Error("Expected order paid with Affirm to be financed")
+	}
+	if (PaymentMethod{DisplayName: "Klarna"}).Provider() != "Klarna" {
+		t.Error("Expected group payment method to resolve its provider")
+	}
+}
@@ -0,0 +1,265 @@
+// Package walmarttest provides an in-memory walmart.WalmartAPI for unit
+// tests of code built on the client.
+package walmarttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+)
+
+// FakeClient serves orders and history from memory. The zero value is ready
+// to use; populate it with AddOrder, AddSummary or the fixture loaders.
+type FakeClient struct {
+	PageSize int   // History page size when a request has no limit, defaults to walmart.DefaultPurchaseHistoryLimit
+	Err      error // Returned by every call when set, e.g. walmart.ErrSessionExpired
+
+	mu      sync.Mutex
+	orders  map[string]*walmart.Order
+	history []walmart.OrderSummary
+	calls   []string
+}
+
+var _ walmart.WalmartAPI = (*FakeClient)(nil)
+
+// NewFakeClient returns a FakeClient holding the given orders
+func NewFakeClient(orders ...*walmart.Order) *FakeClient {
+	f := &FakeClient{}
+	for _, order := range orders {
+		f.AddOrder(order)
+	}
+	return f
+}
+
+// AddOrder makes order available to GetOrder
+func (f *FakeClient) AddOrder(order *walmart.Order) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.orders == nil {
+		f.orders = make(map[string]*walmart.Order)
+	}
+	f.orders[order.ID] = order
+}
+
+// AddSummary appends history entries, newest first like Walmart returns them
+func (f *FakeClient) AddSummary(summaries ...walmart.OrderSummary) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history = append(f.history, summaries...)
+}
+
+// LoadOrderFixture adds the order from a captured getOrder response
+func (f *FakeClient) LoadOrderFixture(path string) (*walmart.Order, error) {
+	var resp walmart.OrderResponse
+	if err := readJSON(path, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Order == nil {
+		return nil, fmt.Errorf("%s: no order data", path)
+	}
+	f.AddOrder(resp.Data.Order)
+	return resp.Data.Order, nil
+}
+
+// LoadHistoryFixture adds the entries from a captured PurchaseHistoryV2 response
+func (f *FakeClient) LoadHistoryFixture(path string) error {
+	var resp walmart.PurchaseHistoryResponse
+	if err := readJSON(path, &resp); err != nil {
+		return err
+	}
+	f.AddSummary(resp.Data.OrderHistoryV2.OrderGroups...)
+	return nil
+}
+
+// Calls returns the names of the methods called so far, in order
+func (f *FakeClient) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// GetOrder implements walmart.WalmartAPI; isInStore is ignored
+func (f *FakeClient) GetOrder(orderID string, isInStore bool) (*walmart.Order, error) {
+	f.record("GetOrder")
+	return f.order(orderID)
+}
+
+// GetOrderAutoDetect implements walmart.WalmartAPI
+func (f *FakeClient) GetOrderAutoDetect(orderID string) (*walmart.Order, error) {
+	f.record("GetOrderAutoDetect")
+	return f.order(orderID)
+}
+
+// GetDeliveryOrderWithTip implements walmart.WalmartAPI
+func (f *FakeClient) GetDeliveryOrderWithTip(orderID string) (*walmart.Order, error) {
+	f.record("GetDeliveryOrderWithTip")
+	order, err := f.order(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.PriceDetails != nil && order.PriceDetails.TotalWithTip == nil {
+		order.CalculateTotalWithTip()
+	}
+	return order, nil
+}
+
+// GetPurchaseHistory implements walmart.WalmartAPI. Search matches item
+// names and Type matches the order or fulfillment type; the cursor is an
+// offset. Timestamps and filter IDs are ignored.
+func (f *FakeClient) GetPurchaseHistory(req walmart.PurchaseHistoryRequest) (*walmart.PurchaseHistoryResponse, error) {
+	f.record("GetPurchaseHistory")
+	return f.page(req)
+}
+
+// GetRecentOrders implements walmart.WalmartAPI
+func (f *FakeClient) GetRecentOrders(limit int) ([]walmart.OrderSummary, error) {
+	f.record("GetRecentOrders")
+	return f.summaries(walmart.PurchaseHistoryRequest{Limit: limit})
+}
+
+// GetAllOrders implements walmart.WalmartAPI
+func (f *FakeClient) GetAllOrders(maxPages int) ([]walmart.OrderSummary, error) {
+	f.record("GetAllOrders")
+
+	var all []walmart.OrderSummary
+	cursor := ""
+	for page := 0; page < maxPages; page++ {
+		resp, err := f.page(walmart.PurchaseHistoryRequest{Cursor: cursor, Limit: 20})
+		if err != nil {
+			return all, fmt.Errorf("failed on page %d: %w", page+1, err)
+		}
+		all = append(all, resp.Data.OrderHistoryV2.OrderGroups...)
+		cursor = resp.Data.OrderHistoryV2.PageInfo.NextPageCursor
+		if cursor == "" {
+			break
+		}
+	}
+	return all, nil
+}
+
+// SearchOrders implements walmart.WalmartAPI
+func (f *FakeClient) SearchOrders(searchTerm string, limit int) ([]walmart.OrderSummary, error) {
+	f.record("SearchOrders")
+	return f.summaries(walmart.PurchaseHistoryRequest{Search: searchTerm, Limit: limit})
+}
+
+// GetOrdersByType implements walmart.WalmartAPI
+func (f *FakeClient) GetOrdersByType(orderType string, limit int) ([]walmart.OrderSummary, error) {
+	f.record("GetOrdersByType")
+	return f.summaries(walmart.PurchaseHistoryRequest{Type: &orderType, Limit: limit})
+}
+
+func (f *FakeClient) record(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, method)
+}
+
+func (f *FakeClient) order(orderID string) (*walmart.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, &walmart.APIError{
+			Operation:  walmart.OperationGetOrder,
+			StatusCode: 200,
+			Kind:       walmart.ErrOrderNotFound,
+		}
+	}
+	return order, nil
+}
+
+func (f *FakeClient) summaries(req walmart.PurchaseHistoryRequest) ([]walmart.OrderSummary, error) {
+	resp, err := f.page(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data.OrderHistoryV2.OrderGroups, nil
+}
+
+func (f *FakeClient) page(req walmart.PurchaseHistoryRequest) (*walmart.PurchaseHistoryResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var matched []walmart.OrderSummary
+	for _, summary := range f.history {
+		if matches(summary, req) {
+			matched = append(matched, summary)
+		}
+	}
+
+	offset := 0
+	if req.Cursor != "" {
+		n, err := strconv.Atoi(req.Cursor)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", req.Cursor)
+		}
+		offset = n
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = f.PageSize
+	}
+	if limit <= 0 {
+		limit = walmart.DefaultPurchaseHistoryLimit
+	}
+	end := offset + limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	resp := &walmart.PurchaseHistoryResponse{}
+	resp.Data.OrderHistoryV2.OrderGroups = matched[offset:end]
+	if end < len(matched) {
+		resp.Data.OrderHistoryV2.PageInfo.NextPageCursor = strconv.Itoa(end)
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		resp.Data.OrderHistoryV2.PageInfo.PrevPageCursor = strconv.Itoa(prev)
+	}
+	return resp, nil
+}
+
+func matches(summary walmart.OrderSummary, req walmart.PurchaseHistoryRequest) bool {
+	if req.Type != nil && *req.Type != summary.Type && *req.Type != summary.FulfillmentType {
+		return false
+	}
+	if req.Search == "" {
+		return true
+	}
+	search := strings.ToLower(req.Search)
+	for _, item := range summary.Items {
+		if strings.Contains(strings.ToLower(item.Name), search) {
+			return true
+		}
+	}
+	return false
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path) //nolint:gosec // fixture path comes from the test
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package walmarttest_test
+
+import (
+	"errors"
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+	"github.com/eshaffer321/walmart-client-go/walmarttest"
+)
+
+// monthlyTotal stands in for downstream code that depends on the interface
+func monthlyTotal(api walmart.WalmartAPI, orderIDs []string) (float64, error) {
+	total := 0.0
+	for _, id := range orderIDs {
+		order, err := api.GetOrder(id, true)
+		if err != nil {
+			return 0, err
+		}
+		if charged, ok := order.ChargedTotal(); ok {
+			total += charged
+		}
+	}
+	return total, nil
+}
+
+func TestFakeClientFixtures(t *testing.T) {
+	fake := walmarttest.NewFakeClient()
+	order, err := fake.LoadOrderFixture("../testdata/payloads/get_order_in_store.json")
+	if err != nil {
+		t.Fatalf("LoadOrderFixture failed: %v", err)
+	}
+	if err := fake.LoadHistoryFixture("../testdata/payloads/purchase_history.json"); err != nil {
+		t.Fatalf("LoadHistoryFixture failed: %v", err)
+	}
+
+	want, _ := order.ChargedTotal()
+	total, err := monthlyTotal(fake, []string{order.ID})
+	if err != nil || total != want {
+		t.Errorf("Expected %.2f, got %.2f (%v)", want, total, err)
+	}
+
+	found, err := fake.SearchOrders("cracker", 10)
+	if err != nil || len(found) != 1 || found[0].OrderID != order.ID {
+		t.Errorf("Expected the fixture order from search, got %+v (%v)", found, err)
+	}
+
+	if _, err := fake.GetOrder("missing", true); !errors.Is(err, walmart.ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 || calls[0] != "GetOrder" || calls[1] != "SearchOrders" {
+		t.Errorf("Unexpected calls: %v", calls)
+	}
+}
+
+func TestFakeClientPagination(t *testing.T) {
+	fake := &walmarttest.FakeClient{}
+	delivery := "DELIVERY"
+	for i := 0; i < 45; i++ {
+		summary := walmart.OrderSummary{OrderID: string(rune('A' + i)), Type: "IN_STORE"}
+		if i%3 == 0 {
+			summary.Type = delivery
+		}
+		fake.AddSummary(summary)
+	}
+
+	all, err := fake.GetAllOrders(10)
+	if err != nil || len(all) != 45 {
+		t.Fatalf("Expected 45 orders across pages, got %d (%v)", len(all), err)
+	}
+	if limited, _ := fake.GetAllOrders(2); len(limited) != 40 {
+		t.Errorf("Expected maxPages to stop at 40 orders, got %d", len(limited))
+	}
+
+	resp, err := fake.GetPurchaseHistory(walmart.PurchaseHistoryRequest{Type: &delivery, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPurchaseHistory failed: %v", err)
+	}
+	if n := len(resp.Data.OrderHistoryV2.OrderGroups); n != 10 || resp.Data.OrderHistoryV2.PageInfo.NextPageCursor == "" {
+		t.Errorf("Expected a full first page of deliveries with a cursor, got %d", n)
+	}
+
+	fake.Err = walmart.ErrSessionExpired
+	if _, err := fake.GetRecentOrders(5); !errors.Is(err, walmart.ErrSessionExpired) {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package walmarttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// RecorderMode selects whether a Recorder talks to the network
+type RecorderMode int
+
+const (
+	ModeReplay RecorderMode = iota // Serve recorded responses, never touch the network
+	ModeRecord                     // Forward requests and record the exchanges
+)
+
+// redacted replaces personal values in recorded bodies
+const redacted = "REDACTED"
+
+// sensitiveHeaders never reach a cassette
+var sensitiveHeaders = []string{"Cookie", "Set-Cookie", "Authorization"}
+
+// sensitiveFields are JSON keys whose values are replaced at any depth
+var sensitiveFields = map[string]bool{
+	"email": true, "firstName": true, "lastName": true, "phone": true,
+	"addressLineOne": true, "addressLineTwo": true, "postalCode": true,
+	"last4Digits": true,
+}
+
+// sensitivePaths are "parent.key" pairs for keys too generic to redact
+// everywhere, such as the account ID in customer.id
+var sensitivePaths = map[string]bool{
+	"customer.id": true,
+}
+
+// ErrNoInteraction is returned in replay mode for requests with no recording
+var ErrNoInteraction = errors.New("no recorded interaction for request")
+
+// Cassette is the on-disk form of recorded exchanges
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one sanitized request/response pair
+type Interaction struct {
+	Method   string      `json:"method"`
+	Path     string      `json:"path"` // Path and query; the host is not matched
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     string      `json:"body"`
+	replayed bool
+}
+
+// Recorder is an http.RoundTripper for ClientConfig.Transport that records
+// real Walmart exchanges to a fixture file, or replays them offline.
+// Cookies and personal fields are stripped before anything is stored, and
+// bodies that are not JSON, such as HTML pages, are dropped.
+type Recorder struct {
+	Path      string            // Cassette file
+	Mode      RecorderMode      // Record or replay
+	Transport http.RoundTripper // Used when recording; defaults to http.DefaultTransport
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder opens the cassette at path. Replay mode requires it to exist.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // fixture path comes from the test
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.Mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Save writes the recorded interactions to Path
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.Path, data, 0600)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, name := range sensitiveHeaders {
+		header.Del(name)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   sanitizeBody(body),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the method and path
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.cassette.Interactions {
+		in := &r.cassette.Interactions[i]
+		if in.replayed || in.Method != req.Method || in.Path != req.URL.RequestURI() {
+			continue
+		}
+		in.replayed = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Body))),
+			ContentLength: int64(len(in.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+}
+
+// sanitizeBody redacts personal fields in JSON bodies. Other bodies can't
+// be scrubbed reliably, so they are dropped.
+func sanitizeBody(body []byte) string {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return ""
+	}
+	clean, err := json.Marshal(redact(v, ""))
+	if err != nil {
+		return ""
+	}
+	return string(clean)
+}
+
+// redact replaces sensitive values below v, the value of the key parent
+func redact(v interface{}, parent string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			if (sensitiveFields[key] || sensitivePaths[parent+"."+key]) && value != nil {
+				t[key] = redacted
+				continue
+			}
+			t[key] = redact(value, key)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i], parent)
+		}
+	}
+	return v
+}
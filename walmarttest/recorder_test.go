@@ -0,0 +1,107 @@
+package walmarttest_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+	"github.com/eshaffer321/walmart-client-go/walmarttest"
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func newRecordedClient(t *testing.T, baseURL string, transport http.RoundTripper) *walmart.WalmartClient {
+	t.Helper()
+	client, err := walmart.NewWalmartClient(walmart.ClientConfig{
+		CookieDir: t.TempDir(),
+		RateLimit: time.Millisecond,
+		BaseURL:   baseURL,
+		Transport: transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.CookieStore.Set("CID", &walmart.Cookie{Value: "secret-cid"})
+	client.CookieStore.Set("SPID", &walmart.Cookie{Value: "secret-spid"})
+	return client
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "order.json")
+
+	srv := server.New()
+	srv.OnResponse(server.GetOrderHash, nil, server.Response{
+		Status: http.StatusOK,
+		Header: http.Header{"Set-Cookie": {"CID=rotated"}},
+		Body:   `{"data":{"order":{"id":"TEST123","customer":{"id":"ACCT-998877","firstName":"Jane","email":"jane@example.com"}}}}`,
+	})
+
+	recorder, err := walmarttest.NewRecorder(cassette, walmarttest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newRecordedClient(t, srv.URL, recorder).GetOrder("TEST123", true); err != nil {
+		t.Fatalf("GetOrder while recording failed: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	data, _ := os.ReadFile(cassette)
+	for _, leak := range []string{"secret-cid", "rotated", "Jane", "jane@example.com", "ACCT-998877"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Cassette leaks %q:\n%s", leak, data)
+		}
+	}
+
+	// Replay works offline, whatever host the client points at
+	player, err := walmarttest.NewRecorder(cassette, walmarttest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newRecordedClient(t, "http://offline.invalid", player)
+	order, err := client.GetOrder("TEST123", true)
+	if err != nil || order.ID != "TEST123" || order.Customer.ID != "REDACTED" {
+		t.Fatalf("Expected the recorded order, got %+v (%v)", order, err)
+	}
+	if _, err := client.GetOrder("TEST123", true); !errors.Is(err, walmarttest.ErrNoInteraction) {
+		t.Errorf("Expected each interaction to replay once, got %v", err)
+	}
+}
+
+func TestRecorderDropsNonJSONBodies(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "page.json")
+	srv := server.New()
+	defer srv.Close()
+	srv.Enqueue(server.Response{
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"text/html"}},
+		Body:   `<html><span>Hi, Jane</span><script>window.__customer={"id":"ACCT-998877"}</script></html>`,
+	})
+
+	recorder, err := walmarttest.NewRecorder(cassette, walmarttest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", srv.URL+"/orders", nil)
+	resp, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(cassette)
+	for _, leak := range []string{"Jane", "ACCT-998877"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Cassette leaks %q:\n%s", leak, data)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package server_test
+
+import (
+	"testing"
+
+	walmart "github.com/eshaffer321/walmart-client-go"
+	"github.com/eshaffer321/walmart-client-go/walmarttest/server"
+)
+
+func TestHashesMatchClient(t *testing.T) {
+	hashes := walmart.DefaultQueryHashes()
+	if hashes[walmart.OperationGetOrder] != server.GetOrderHash || hashes[walmart.OperationPurchaseHistory] != server.PurchaseHistoryHash {
+		t.Errorf("Fake server hashes differ from the client's: %v", hashes)
+	}
+}
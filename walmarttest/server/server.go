@@ -0,0 +1,258 @@
+// Package server provides an httptest-based fake of Walmart's persisted-query
+// GraphQL endpoints for end-to-end tests of the client.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/eshaffer321/walmart-client-go/internal/queryhash"
+)
+
+// Persisted-query hashes the client currently sends, the same values as
+// walmart.DefaultQueryHashes
+const (
+	GetOrderHash        = queryhash.GetOrder
+	PurchaseHistoryHash = queryhash.PurchaseHistory
+)
+
+// ChallengeBody is a stand-in for the bot challenge page served with 418s
+const ChallengeBody = `<html><body>Robot or human? Activate and hold the button to confirm that you're human.</body></html>`
+
+// Response is a canned reply
+type Response struct {
+	Status int
+	Header http.Header
+	Body   string
+}
+
+// RateLimited returns a 429 with the given Retry-After value
+func RateLimited(retryAfter string) Response {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+	return Response{Status: http.StatusTooManyRequests, Header: header, Body: "Too Many Requests"}
+}
+
+// BotChallenge returns the 418 challenge Walmart serves to flagged sessions
+func BotChallenge() Response {
+	return Response{
+		Status: http.StatusTeapot,
+		Header: http.Header{"Content-Type": {"text/html"}},
+		Body:   ChallengeBody,
+	}
+}
+
+// Request is a recorded incoming call
+type Request struct {
+	Operation string
+	Hash      string
+	Variables map[string]interface{}
+	Header    http.Header
+	Cookies   map[string]string
+}
+
+type route struct {
+	hash      string
+	variables map[string]interface{}
+	response  Response
+}
+
+// Server is a fake walmart.com serving canned responses keyed by operation
+// hash and variables. Requests missing required cookies get a 403 and
+// requests missing required headers get a 418, as the real site does.
+type Server struct {
+	*httptest.Server
+
+	// RequiredCookies must all be present on every request
+	RequiredCookies []string
+	// RequiredHeaders must all be present on every request
+	RequiredHeaders []string
+
+	mu       sync.Mutex
+	routes   []route
+	queue    []Response
+	requests []Request
+}
+
+// New starts a fake server; call Close when done
+func New() *Server {
+	s := &Server{
+		RequiredCookies: []string{"CID", "SPID"},
+		RequiredHeaders: []string{"x-apollo-operation-name", "x-o-platform"},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// On registers body as the 200 reply for hash. When variables is non-nil
+// it must be a subset of the request variables for the route to match.
+// Later registrations take precedence.
+func (s *Server) On(hash string, variables map[string]interface{}, body string) {
+	s.OnResponse(hash, variables, Response{Status: http.StatusOK, Body: body})
+}
+
+// OnResponse registers an arbitrary reply for hash and variables
+func (s *Server) OnResponse(hash string, variables map[string]interface{}, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route{hash: hash, variables: variables, response: resp})
+}
+
+// Enqueue makes the next requests, in order, receive these replies before
+// normal routing resumes, e.g. to simulate a 429 followed by success
+func (s *Server) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, responses...)
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RedirectClient returns an HTTP client that sends every request to this
+// server regardless of the host in the URL, for clients with fixed URLs
+func (s *Server) RedirectClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{
+		Transport: redirectTransport{target: target, base: s.Client().Transport},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+type redirectTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.URL.Scheme = t.target.Scheme
+	clone.URL.Host = t.target.Host
+	clone.Host = t.target.Host
+	return t.base.RoundTrip(clone)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	recorded := parseRequest(r)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recorded)
+
+	var resp Response
+	switch {
+	case len(s.queue) > 0:
+		resp = s.queue[0]
+		s.queue = s.queue[1:]
+	case missing(s.RequiredCookies, func(name string) bool { _, ok := recorded.Cookies[name]; return ok }):
+		resp = Response{Status: http.StatusForbidden, Body: "Forbidden"}
+	case missing(s.RequiredHeaders, func(name string) bool { return r.Header.Get(name) != "" }):
+		resp = BotChallenge()
+	default:
+		resp = s.match(recorded)
+	}
+	s.mu.Unlock()
+
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write([]byte(resp.Body))
+}
+
+// match finds the newest route for the request; callers hold s.mu
+func (s *Server) match(req Request) Response {
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		rt := s.routes[i]
+		if rt.hash == req.Hash && (rt.variables == nil || isSubset(rt.variables, req.Variables)) {
+			return rt.response
+		}
+	}
+	return Response{Status: http.StatusNotFound, Body: `{"errors":[{"message":"PersistedQueryNotFound"}]}`}
+}
+
+// parseRequest extracts the operation from /orchestra/<svc>/graphql/<op>/<hash>
+func parseRequest(r *http.Request) Request {
+	req := Request{
+		Header:  r.Header.Clone(),
+		Cookies: make(map[string]string),
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) >= 2 {
+		req.Operation = parts[len(parts)-2]
+		req.Hash = parts[len(parts)-1]
+	}
+
+	if raw := r.URL.Query().Get("variables"); raw != "" {
+		_ = json.Unmarshal([]byte(raw), &req.Variables)
+	}
+
+	for _, cookie := range r.Cookies() {
+		req.Cookies[cookie.Name] = cookie.Value
+	}
+	return req
+}
+
+func missing(names []string, present func(string) bool) bool {
+	for _, name := range names {
+		if !present(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubset reports whether every key in want matches got, recursing into
+// nested objects; values are compared after a JSON round trip
+func isSubset(want, got map[string]interface{}) bool {
+	for key, wantValue := range want {
+		gotValue, ok := got[key]
+		if !ok {
+			return false
+		}
+
+		wantMap, wantIsMap := normalize(wantValue).(map[string]interface{})
+		gotMap, gotIsMap := gotValue.(map[string]interface{})
+		if wantIsMap && gotIsMap {
+			if !isSubset(wantMap, gotMap) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(normalize(wantValue), gotValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize converts Go values to their decoded-JSON form (e.g. int to float64)
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
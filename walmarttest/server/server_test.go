@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, path string, variables map[string]interface{}, cookies bool) *http.Response {
+	t.Helper()
+	vars, _ := json.Marshal(variables)
+	req, _ := http.NewRequest("GET", "https://www.walmart.com"+path+"?variables="+url.QueryEscape(string(vars)), nil)
+	req.Header.Set("x-apollo-operation-name", "getOrder")
+	req.Header.Set("x-o-platform", "rweb")
+	if cookies {
+		req.Header.Set("Cookie", "CID=1; SPID=2")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	return resp
+}
+
+func TestServerRouting(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	client := srv.RedirectClient()
+
+	srv.On(GetOrderHash, nil, `{"fallback":true}`)
+	srv.On(GetOrderHash, map[string]interface{}{"orderId": "A", "opts": map[string]interface{}{"n": 1}}, `{"order":"A"}`)
+
+	path := "/orchestra/orders/graphql/getOrder/" + GetOrderHash
+
+	resp := get(t, client, path, map[string]interface{}{"orderId": "A", "opts": map[string]interface{}{"n": 1, "x": 2}}, true)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"order":"A"}` {
+		t.Errorf("Expected variable-specific route, got %s", body)
+	}
+
+	resp = get(t, client, path, map[string]interface{}{"orderId": "B"}, true)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"fallback":true}` {
+		t.Errorf("Expected fallback route, got %s", body)
+	}
+
+	resp = get(t, client, "/orchestra/orders/graphql/getOrder/unknown", nil, true)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown hash, got %d", resp.StatusCode)
+	}
+
+	resp = get(t, client, path, nil, false)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 without cookies, got %d", resp.StatusCode)
+	}
+
+	srv.Enqueue(RateLimited("3"))
+	resp = get(t, client, path, nil, true)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "3" {
+		t.Errorf("Expected queued 429, got %d", resp.StatusCode)
+	}
+
+	if got := len(srv.Requests()); got != 5 {
+		t.Errorf("Expected 5 recorded requests, got %d", got)
+	}
+	if srv.Requests()[0].Cookies["CID"] != "1" {
+		t.Error("Cookies were not recorded")
+	}
+}